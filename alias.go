@@ -0,0 +1,55 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+)
+
+// Alias - registers alias as another name for the canonical flag, e.g.
+// Alias("verbose", "v").  Both names share the same Value, so setting
+// either one sets the same pointer and counts as set for both, with the
+// last occurrence winning when both are given.  In the usage the alias is
+// listed alongside the canonical name.  Panics if canonical is not
+// defined, or alias already is, same as the flag package does for
+// redefined flags.
+func (ndf *NDFlagSet) Alias(canonical, alias string) {
+	fl := ndf.Lookup(canonical)
+	if fl == nil {
+		panic(fmt.Sprintf("flag alias -%s for undefined flag -%s", alias, canonical))
+	}
+	if c, ok := ndf.aliases[canonical]; ok {
+		// aliasing an alias, point at the real flag.
+		canonical = c
+	}
+	ndf.FlagSet.Var(fl.Value, alias, fl.Usage)
+	if t, ok := fl.Value.(*trackedValue); ok {
+		ndf.tracked[alias] = t
+	}
+	if ndf.aliases == nil {
+		ndf.aliases = make(map[string]string)
+	}
+	ndf.aliases[alias] = canonical
+}
+
+// aliasesOf - the sorted aliases of the canonical flag name.
+func (ndf *NDFlagSet) aliasesOf(canonical string) []string {
+	var out []string
+	for a, c := range ndf.aliases {
+		if c == canonical {
+			out = append(out, a)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// visitCanonical - VisitAll, skipping aliases so each flag is only
+// visited once.
+func (ndf *NDFlagSet) visitCanonical(fn func(*flag.Flag)) {
+	ndf.VisitAll(func(fl *flag.Flag) {
+		if _, ok := ndf.aliases[fl.Name]; !ok {
+			fn(fl)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package nodefflag
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	verbose := fs.NDBool("verbose", false, "verbose output")
+	fs.Alias("verbose", "v")
+	level := fs.ZVInt("level", 0, "level")
+	fs.Alias("level", "l")
+	fs.Alias("l", "lvl")
+
+	if err := fs.Parse([]string{"-v", "-level=1", "-lvl=2"}); err != nil {
+		t.Fatal(err)
+	}
+	if *verbose == nil || !**verbose {
+		t.Error("expected -v to set verbose")
+	}
+	if !fs.IsSet("verbose") || !fs.IsSet("v") {
+		t.Error("expected both names to report set")
+	}
+	if *level != 2 {
+		t.Errorf("expected last occurrence to win, got %d", *level)
+	}
+	if v, ok := fs.IntValue("l"); !ok || v != 2 {
+		t.Errorf("bad level via alias: %d %v", v, ok)
+	}
+
+	if m := fs.SetValues(); len(m) != 2 {
+		t.Errorf("aliases should not be listed separately: %v", m)
+	}
+
+	buf := &bytes.Buffer{}
+	fs.SetOutput(buf)
+	fs.Usage()
+	out := buf.String()
+	if !strings.Contains(out, "  -level, -l, -lvl value\n") || !strings.Contains(out, "  -verbose, -v\n") {
+		t.Errorf("aliases should be grouped in usage:\n%s", out)
+	}
+	if strings.Contains(out, "\n  -v\n") || strings.Contains(out, "\n  -l value") {
+		t.Errorf("aliases should not have their own entries:\n%s", out)
+	}
+}
+
+func TestAliasUndefined(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic aliasing an undefined flag")
+		}
+	}()
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.Alias("nope", "n")
+}
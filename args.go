@@ -0,0 +1,137 @@
+package nodefflag
+
+import "strings"
+
+// SetValueSeparator - lets flags be given as -name<sep>value, e.g. with
+// ":" as -name:value, for wrappers that pass them that way.  -name=value
+// keeps working, and only the first separator in an argument counts, so
+// -url:http://host is the url http://host.  "" or "=" puts it back to
+// just =.
+func (ndf *NDFlagSet) SetValueSeparator(sep string) {
+	if sep == "=" {
+		sep = ""
+	}
+	ndf.valueSep = sep
+}
+
+// normalizeArgs - args with whatever the flag set is configured to accept
+// on top of the flag package's syntax rewritten into it: value
+// separators, bundled short flags and abbreviated names, with unknown
+// flags handed to the unknown handler.  It walks the arguments as the
+// flag package does, stopping at the first non-flag argument, "--", or
+// anything the flag package will reject anyway, which is left for it to
+// report.
+func (ndf *NDFlagSet) normalizeArgs(args []string) ([]string, error) {
+	if !ndf.prefixMatch && ndf.valueSep == "" && !ndf.gnuStyle && ndf.unknown == nil {
+		return args, nil
+	}
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		s := args[i]
+		if len(s) < 2 || s[0] != '-' || s == "--" {
+			return append(out, args[i:]...), nil
+		}
+		dashes := "-"
+		if s[1] == '-' {
+			dashes = "--"
+		}
+		name := s[len(dashes):]
+		if name == "" || name[0] == '-' || name[0] == '=' {
+			return append(out, args[i:]...), nil
+		}
+		value, hasValue := "", false
+		if j, n := ndf.valueIndex(name); j >= 0 {
+			name, value, hasValue = name[:j], "="+name[j+n:], true
+		}
+
+		fl := ndf.Lookup(name)
+		if fl == nil && ndf.gnuStyle && dashes == "-" && !hasValue {
+			if bundle, ok := ndf.bundled(name); ok {
+				out = append(out, bundle...)
+				continue
+			}
+		}
+		if fl == nil && ndf.prefixMatch && name != "h" && name != "help" {
+			var err error
+			if fl, err = ndf.prefixFlag(name); err != nil {
+				return nil, err
+			}
+		}
+		if fl == nil && ndf.unknown != nil && name != "h" && name != "help" {
+			if err := ndf.unknown(name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if fl == nil {
+			return append(out, args[i:]...), nil
+		}
+		out = append(out, dashes+fl.Name+value)
+		if hasValue || i+1 == len(args) {
+			continue
+		}
+		b, ok := fl.Value.(interface {
+			IsBoolFlag() bool
+		})
+		if !ok || !b.IsBoolFlag() {
+			// the next argument is the value, don't take it for a flag
+			i++
+			out = append(out, args[i])
+		}
+	}
+	return out, nil
+}
+
+// SetUnknownHandler - has fn called with the name of every flag that
+// isn't defined, instead of the parse failing on it, e.g. to ignore or
+// collect flags meant for another program.  If fn returns nil the
+// argument is dropped and parsing goes on, otherwise the parse fails with
+// its error.  Only -name and -name=value are dropped, a value in the next
+// argument can't be told apart from a positional one and is left, which
+// ends the flags as usual.  -h and -help still print the usage when not
+// defined.  nil puts back the flag package's behavior.
+func (ndf *NDFlagSet) SetUnknownHandler(fn func(name string) error) {
+	ndf.unknown = fn
+}
+
+// SetGNUStyle - with on, single letter bool flags can be bundled GNU
+// style, so -abc is -a -b -c, as long as there's no flag named abc and
+// each of a, b and c is a bool flag.  --name=value and --name value work
+// either way, as they do in the flag package.
+func (ndf *NDFlagSet) SetGNUStyle(on bool) {
+	ndf.gnuStyle = on
+}
+
+// bundled - the separate flags of a -abc style bundle, if every letter
+// of name is a single letter bool flag.
+func (ndf *NDFlagSet) bundled(name string) ([]string, bool) {
+	var out []string
+	for _, r := range name {
+		fl := ndf.Lookup(string(r))
+		if fl == nil {
+			return nil, false
+		}
+		b, ok := fl.Value.(interface {
+			IsBoolFlag() bool
+		})
+		if !ok || !b.IsBoolFlag() {
+			return nil, false
+		}
+		out = append(out, "-"+string(r))
+	}
+	return out, true
+}
+
+// valueIndex - where the value separator in a flag argument is, = or
+// the one set with SetValueSeparator, whichever comes first, and its
+// length.  -1 if there's none.
+func (ndf *NDFlagSet) valueIndex(arg string) (int, int) {
+	i := strings.Index(arg, "=")
+	if ndf.valueSep == "" {
+		return i, 1
+	}
+	if j := strings.Index(arg, ndf.valueSep); j >= 0 && (i < 0 || j < i) {
+		return j, len(ndf.valueSep)
+	}
+	return i, 1
+}
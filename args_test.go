@@ -0,0 +1,159 @@
+package nodefflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestValueSeparator(t *testing.T) {
+	for _, sep := range []string{":", "="} {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.SetValueSeparator(sep)
+		name := fs.NDString("name", "", "string value")
+		url := fs.NDString("url", "", "string value")
+		eq := fs.NDString("eq", "", "string value")
+		port := fs.ZVInt("port", 0, "int value")
+		debug := fs.NDBool("debug", false, "bool value")
+		next := fs.NDString("next", "", "string value")
+
+		args := []string{
+			"-name" + sep + "a" + sep + "b",
+			"--url" + sep + "http://host:80/x",
+			"-eq=k:v",
+			"-port" + sep + "8",
+			"-debug" + sep + "false",
+			"-next", "-x:y",
+			"rest:z",
+		}
+		if err := fs.Parse(args); err != nil {
+			t.Errorf("%q: %v", sep, err)
+			continue
+		}
+		if **name != "a"+sep+"b" {
+			t.Errorf("%q: only the first separator should count, got %q", sep, **name)
+		}
+		if **url != "http://host:80/x" {
+			t.Errorf("%q: bad url %q", sep, **url)
+		}
+		if **eq != "k:v" {
+			t.Errorf("%q: = should still work, got %q", sep, **eq)
+		}
+		if *port != 8 || **debug {
+			t.Errorf("%q: bad port / debug: %d %v", sep, *port, **debug)
+		}
+		if **next != "-x:y" {
+			t.Errorf("%q: a value argument shouldn't be rewritten, got %q", sep, **next)
+		}
+		if a := fs.Args(); len(a) != 1 || a[0] != "rest:z" {
+			t.Errorf("%q: bad rest %v", sep, a)
+		}
+	}
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDString("name", "", "string value")
+	if err := fs.Parse([]string{"-name:x"}); err == nil {
+		t.Errorf("-name:x should be an unknown flag by default")
+	}
+}
+
+func TestGNUStyle(t *testing.T) {
+	newFS := func(on bool) (*NDFlagSet, **bool, **bool, *int, **string) {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.SetGNUStyle(on)
+		a := fs.NDBool("a", false, "bool value")
+		b := fs.NDBool("b", false, "bool value")
+		v := fs.ZVCount("v", "verbosity")
+		name := fs.NDString("name", "", "string value")
+		fs.NDString("n", "", "not a bool")
+		return fs, a, b, v, name
+	}
+
+	fs, a, b, v, name := newFS(true)
+	if err := fs.Parse([]string{"-abvv", "--name=x", "rest"}); err != nil {
+		t.Fatal(err)
+	}
+	if *a == nil || !**a || *b == nil || !**b || *v != 2 {
+		t.Errorf("-abvv should set -a, -b and -v twice: %v %v %d", *a, *b, *v)
+	}
+	if **name != "x" {
+		t.Errorf("bad name %q", **name)
+	}
+
+	fs, _, _, _, name = newFS(true)
+	if err := fs.Parse([]string{"--name", "y"}); err != nil || **name != "y" {
+		t.Errorf("--name y should work: %v", err)
+	}
+	if err := fs.Parse([]string{"-an"}); err == nil {
+		t.Errorf("only bool flags bundle, -n isn't one")
+	}
+	if err := fs.Parse([]string{"-ab=true"}); err == nil {
+		t.Errorf("a bundle can't take a value")
+	}
+
+	fs, _, _, _, _ = newFS(false)
+	if err := fs.Parse([]string{"-ab"}); err == nil {
+		t.Errorf("without GNU style -ab is a flag of its own")
+	}
+	if err := fs.Parse([]string{"-a", "-b", "--name=z"}); err != nil {
+		t.Errorf("separate flags should still work: %v", err)
+	}
+}
+
+func TestUnknownHandler(t *testing.T) {
+	newSet := func() (*NDFlagSet, **string) {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		return fs, fs.NDString("name", "", "name")
+	}
+	args := []string{"-x", "-name", "n", "--y=1", "rest"}
+
+	// ignore
+	fs, name := newSet()
+	fs.SetUnknownHandler(func(string) error { return nil })
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if **name != "n" || fmt.Sprint(fs.Args()) != "[rest]" {
+		t.Errorf("unknown flags should be skipped, got %q and %v", **name, fs.Args())
+	}
+
+	// collect
+	fs, _ = newSet()
+	var unknown []string
+	fs.SetUnknownHandler(func(name string) error {
+		unknown = append(unknown, name)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(unknown) != "[x y]" {
+		t.Errorf("expected x and y collected, got %v", unknown)
+	}
+
+	// error
+	fs, _ = newSet()
+	errBad := errors.New("bad flag")
+	fs.SetUnknownHandler(func(name string) error {
+		if name == "y" {
+			return errBad
+		}
+		return nil
+	})
+	if err := fs.Parse(args); err != errBad {
+		t.Errorf("expected the handler's error, got %v", err)
+	}
+
+	// -h still asks for help
+	fs, _ = newSet()
+	fs.SetUnknownHandler(func(string) error { return nil })
+	if err := fs.Parse([]string{"-h"}); err != flag.ErrHelp {
+		t.Errorf("expected ErrHelp, got %v", err)
+	}
+}
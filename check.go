@@ -0,0 +1,177 @@
+package nodefflag
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Require - marks the named flags as required, see CheckRequired.
+func (ndf *NDFlagSet) Require(names ...string) {
+	for _, name := range names {
+		if !contains(ndf.required, name) {
+			ndf.required = append(ndf.required, name)
+		}
+	}
+}
+
+// CheckRequired - call after Parse, returns an error naming every
+// required flag that was not set, or nil if they all were.
+func (ndf *NDFlagSet) CheckRequired() error {
+	var missing []string
+	for _, name := range ndf.required {
+		if !ndf.IsSet(name) {
+			missing = append(missing, "-"+name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("missing required flags: %s", strings.Join(missing, ", "))
+}
+
+// AddValidator - attaches a validator to the named flag, for rejecting
+// values that parse fine but aren't acceptable, e.g. a port outside
+// 1-65535.  Once Parse has processed the arguments, fn is called with the
+// flag's Get() result, but only if the flag was set.  Validator errors
+// are collected and returned from Parse together.  A flag may have more
+// than one validator, they run in the order added.
+func (ndf *NDFlagSet) AddValidator(name string, fn func(interface{}) error) {
+	ndf.addValidator(name, func(_ context.Context, v interface{}) error {
+		return fn(v)
+	})
+}
+
+// AddContextValidator - AddValidator for validators that need a context,
+// e.g. to cancel a network lookup.  fn gets the context passed to
+// ParseContext, or context.Background for the other Parse methods.
+func (ndf *NDFlagSet) AddContextValidator(name string, fn func(context.Context, interface{}) error) {
+	ndf.addValidator(name, fn)
+}
+
+// validator - how validators are kept, whichever way they were added.
+type validator func(context.Context, interface{}) error
+
+func (ndf *NDFlagSet) addValidator(name string, fn validator) {
+	if ndf.validators == nil {
+		ndf.validators = make(map[string][]validator)
+	}
+	ndf.validators[name] = append(ndf.validators[name], fn)
+}
+
+// runValidators - runs the validators of every set flag, in flag name
+// order.
+func (ndf *NDFlagSet) runValidators() error {
+	var errs []error
+	ndf.VisitAll(func(fl *flag.Flag) {
+		errs = append(errs, ndf.validateFlag(fl)...)
+	})
+	return errors.Join(errs...)
+}
+
+// validateFlag - runs the validators of fl, if it's set.
+func (ndf *NDFlagSet) validateFlag(fl *flag.Flag) []error {
+	fns := ndf.validators[fl.Name]
+	if len(fns) == 0 || !ndf.IsSet(fl.Name) {
+		return nil
+	}
+	g, ok := fl.Value.(flag.Getter)
+	if !ok {
+		return nil
+	}
+	var errs []error
+	for _, fn := range fns {
+		if err := fn(ndf.Context(), g.Get()); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for flag -%s: %w", fl.Name, err))
+		}
+	}
+	return errs
+}
+
+// checkCount - the NDStringSliceN check, a negative max is no bound.
+func checkCount(n, min, max int) error {
+	if n >= min && (max < 0 || n <= max) {
+		return nil
+	}
+	switch {
+	case max < 0:
+		return fmt.Errorf("got %d values, want at least %d", n, min)
+	case min == max:
+		return fmt.Errorf("got %d values, want %d", n, min)
+	}
+	return fmt.Errorf("got %d values, want %d to %d", n, min, max)
+}
+
+// MutuallyExclusive - records a group of flags of which at most one may
+// be set, e.g. MutuallyExclusive("json", "yaml").  Parse returns an error
+// naming the conflicting flags if more than one of them is.
+func (ndf *NDFlagSet) MutuallyExclusive(names ...string) {
+	ndf.exclusive = append(ndf.exclusive, names)
+}
+
+// Validate - runs every check a Parse would, in order the required
+// flags, the validators and the mutually exclusive groups, returning all
+// that fail as one error.  Each failure stays inspectable with errors.Is
+// and errors.As.  Parse already runs the validators and exclusive groups,
+// this is for checking required flags in the same go, or values set
+// afterwards, e.g. with ApplyMap.  Unlike Parse it just returns the
+// error, without printing it or honoring ErrorHandling.
+func (ndf *NDFlagSet) Validate() error {
+	return errors.Join(ndf.CheckRequired(), ndf.runValidators(), ndf.checkExclusive())
+}
+
+func (ndf *NDFlagSet) checkExclusive() error {
+	var errs []error
+	for _, group := range ndf.exclusive {
+		var set []string
+		for _, name := range group {
+			if ndf.IsSet(name) {
+				set = append(set, "-"+name)
+			}
+		}
+		if len(set) > 1 {
+			errs = append(errs, fmt.Errorf("flags %s are mutually exclusive", strings.Join(set, ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNoDuplicates - with on, Parse returns an error if a flag is given
+// more than once, rather than the flag package's last one wins.  Flags
+// meant to be repeated, the slice, map, count and func flags, are exempt.
+// Aliases count as the same flag, so -v and -verbose together are a
+// duplicate.
+func (ndf *NDFlagSet) SetNoDuplicates(on bool) {
+	ndf.noDups = on
+}
+
+// checkDuplicates - with SetNoDuplicates, an error naming every scalar
+// flag that was set more than once.
+func (ndf *NDFlagSet) checkDuplicates() error {
+	if !ndf.noDups {
+		return nil
+	}
+	var errs []error
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		t, ok := fl.Value.(*trackedValue)
+		if !ok || t.count < 2 {
+			return
+		}
+		if _, ok := t.Value.(repeater); ok {
+			return
+		}
+		errs = append(errs, fmt.Errorf("flag -%s given %d times", fl.Name, t.count))
+	})
+	return errors.Join(errs...)
+}
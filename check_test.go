@@ -0,0 +1,301 @@
+package nodefflag
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestCheckRequired(t *testing.T) {
+	fs := nfs()
+	fs.Require("test_int", "test_string", "test_bool")
+	fs.Require("test_duration", "test_int")
+
+	if err := fs.Parse([]string{"-test_bool=false"}); err != nil {
+		t.Fatal(err)
+	}
+	err := fs.CheckRequired()
+	if err == nil {
+		t.Fatal("expected missing flags error")
+	}
+	want := "missing required flags: -test_int, -test_string, -test_duration"
+	if err.Error() != want {
+		t.Errorf("bad error:\n%s\nwant:\n%s", err, want)
+	}
+
+	_ = fs.Set("test_int", "0")
+	_ = fs.Set("test_string", "")
+	_ = fs.Set("test_duration", "1s")
+	if err := fs.CheckRequired(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestCheckRequiredZV(t *testing.T) {
+	fs := zvs()
+	fs.Require("test_uint")
+	if err := fs.CheckRequired(); err == nil {
+		t.Error("expected missing zv flag error")
+	}
+	if err := fs.Parse([]string{"-test_uint=0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.CheckRequired(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	port := func(v interface{}) error {
+		if p := *(v.(*int)); p < 1 || p > 65535 {
+			return fmt.Errorf("port %d out of range 1-65535", p)
+		}
+		return nil
+	}
+	nonEmpty := func(v interface{}) error {
+		if v.(string) == "" {
+			return errors.New("must not be empty")
+		}
+		return nil
+	}
+	called := false
+	never := func(v interface{}) error {
+		called = true
+		return errors.New("should not run")
+	}
+
+	newFS := func() *NDFlagSet {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.NDInt("port", 80, "port")
+		fs.AddValidator("port", port)
+		fs.ZVString("name", "", "name")
+		fs.AddValidator("name", nonEmpty)
+		fs.ZVString("unset", "", "never set")
+		fs.AddValidator("unset", never)
+		return fs
+	}
+
+	if err := newFS().Parse([]string{"-port=8080", "-name=x"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	err := newFS().Parse([]string{"-port=70000", "-name="})
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	want := "invalid value for flag -name: must not be empty\n" +
+		"invalid value for flag -port: port 70000 out of range 1-65535"
+	if err.Error() != want {
+		t.Errorf("bad error:\n%s\nwant:\n%s", err, want)
+	}
+	if called {
+		t.Error("validator ran for an unset flag")
+	}
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"-json"}, ""},
+		{[]string{"-json", "-text=false"}, "flags -json, -text are mutually exclusive"},
+		{[]string{"-yaml", "-text", "-json"}, "flags -json, -yaml, -text are mutually exclusive"},
+	}
+	for _, tt := range tests {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.NDBool("json", false, "json output")
+		fs.ZVBool("yaml", false, "yaml output")
+		fs.NDBool("text", false, "text output")
+		fs.NDBool("other", false, "not in the group")
+		fs.MutuallyExclusive("json", "yaml", "text")
+
+		err := fs.Parse(append(tt.args, "-other"))
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("%v: got error %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestNoDuplicates(t *testing.T) {
+	newFS := func(on bool) *NDFlagSet {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.SetNoDuplicates(on)
+		fs.NDInt("port", 0, "port")
+		fs.ZVString("name", "", "name")
+		fs.Alias("name", "n")
+		fs.NDStringSlice("tag", "repeatable")
+		fs.NDStringMap("label", "repeatable")
+		fs.NDCount("v", "verbosity")
+		return fs
+	}
+
+	ok := []string{"-port=1", "-tag=a", "-tag=b", "-label=a=1", "-label=b=2", "-v", "-v", "-name=x"}
+	if err := newFS(true).Parse(ok); err != nil {
+		t.Errorf("repeatable flags should be allowed: %v", err)
+	}
+
+	dup := []string{"-port=1", "-port=2", "-name=x", "-n=y"}
+	if err := newFS(false).Parse(dup); err != nil {
+		t.Errorf("duplicates should be fine by default: %v", err)
+	}
+	err := newFS(true).Parse(dup)
+	if err == nil {
+		t.Fatal("expected an error for duplicated flags")
+	}
+	for _, want := range []string{"flag -port given 2 times", "flag -name given 2 times"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	type key struct{}
+	var got interface{}
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	name := fs.NDString("name", "", "name")
+	fs.AddContextValidator("name", func(ctx context.Context, v interface{}) error {
+		got = ctx.Value(key{})
+		return ctx.Err()
+	})
+
+	ctx := context.WithValue(context.Background(), key{}, "val")
+	if err := fs.ParseContext(ctx, []string{"-name=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if got != "val" {
+		t.Errorf("validator should get the parse context, got %v", got)
+	}
+	if fs.Context() != context.Background() {
+		t.Errorf("the context shouldn't outlive ParseContext")
+	}
+
+	fs.Reset()
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := fs.ParseContext(cctx, []string{"-name=y"}); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if *name != nil {
+		t.Errorf("nothing should be parsed after cancellation, got %q", **name)
+	}
+
+	if err := fs.Parse([]string{"-name=z"}); err != nil {
+		t.Errorf("plain Parse should use context.Background: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	errLow := errors.New("too low")
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("host", "", "host")
+	fs.ZVString("json", "", "json output")
+	fs.ZVString("yaml", "", "yaml output")
+	fs.Require("host")
+	fs.MutuallyExclusive("json", "yaml")
+	fs.ZVInt("port", 0, "port")
+	fs.AddValidator("port", func(v interface{}) error {
+		if v.(int) < 1024 {
+			return errLow
+		}
+		return nil
+	})
+
+	fs.Set("port", "80")
+	fs.Set("json", "x")
+	fs.Set("yaml", "y")
+	err := fs.Validate()
+	if err == nil {
+		t.Fatal("expected Validate to fail")
+	}
+	if !errors.Is(err, errLow) {
+		t.Errorf("validator errors should be inspectable: %v", err)
+	}
+	want := "missing required flags: -host\n" +
+		"invalid value for flag -port: too low\n" +
+		"flags -json, -yaml are mutually exclusive"
+	if err.Error() != want {
+		t.Errorf("bad error:\n%s\nwant:\n%s", err, want)
+	}
+
+	fs.Reset()
+	fs.Set("host", "h")
+	if err := fs.Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestStringSliceN(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-xy", "1"}, "invalid value for flag -xy: got 1 values, want 2"},
+		{[]string{"-xy", "1", "-xy", "2"}, ""},
+		{[]string{"-xy", "1", "-xy", "2", "-xy", "3"}, "invalid value for flag -xy: got 3 values, want 2"},
+		{[]string{"-tag", "a", "-tag", "b", "-tag", "c", "-tag", "d"}, "invalid value for flag -tag: got 4 values, want 1 to 3"},
+		{[]string{"-tag", "a"}, ""},
+		{[]string{"-id", "a"}, "invalid value for flag -id: got 1 values, want at least 2"},
+		{[]string{"-id", "a", "-id", "b", "-id", "c"}, ""},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.NDStringSliceN("xy", 2, 2, "coordinates")
+		fs.ZVStringSliceN("tag", 1, 3, "tags")
+		fs.NDStringSliceN("id", 2, -1, "ids")
+		err := fs.Parse(test.args)
+		if test.err == "" && err != nil {
+			t.Errorf("%v: expected no error, got %v", test.args, err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("%v: expected error %q, got %v", test.args, test.err, err)
+		}
+	}
+}
+
+func TestStringSliceNCopies(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDStringSliceN("c", 2, 2, "coordinates")
+	fs.ZVStringSliceN("z", 2, 2, "coordinates")
+	want := "invalid value for flag -c: got 1 values, want 2"
+
+	cl := fs.Clone("clone")
+	cl.SetOutput(ioutil.Discard)
+	if err := cl.Parse([]string{"-c", "1"}); err == nil || err.Error() != want {
+		t.Errorf("clone: expected %q, got %v", want, err)
+	}
+	cl = fs.Clone("clone")
+	cl.SetOutput(ioutil.Discard)
+	if err := cl.Parse([]string{"-z", "1", "-z", "2", "-z", "3"}); err == nil || !strings.Contains(err.Error(), "got 3 values") {
+		t.Errorf("clone: expected a count error for -z, got %v", err)
+	}
+	if _, err := fs.DryRunParse([]string{"-c", "1"}); err == nil || err.Error() != want {
+		t.Errorf("dry run: expected %q, got %v", want, err)
+	}
+
+	// absent is fine, unless required
+	if err := fs.Parse(nil); err != nil {
+		t.Errorf("absent flags should pass, got %v", err)
+	}
+	fs.Require("c")
+	if err := fs.Validate(); err == nil || err.Error() != "missing required flags: -c" {
+		t.Errorf("expected a missing required error, got %v", err)
+	}
+}
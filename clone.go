@@ -0,0 +1,185 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// cloner - implemented by the package's Value types, returns a Value with
+// the same definition but a fresh, unset target.
+type cloner interface {
+	clone() flag.Value
+}
+
+func (n *ndv[T]) clone() flag.Value {
+	c := *n
+	c.v = new(*T)
+	return &c
+}
+
+func (z *zvv[T]) clone() flag.Value {
+	c := *z
+	c.v = new(T)
+	c.reset()
+	return &c
+}
+
+func (n *ndsv[T]) clone() flag.Value {
+	c := *n
+	c.v = new(*[]T)
+	return &c
+}
+
+func (z *zvsv[T]) clone() flag.Value {
+	c := *z
+	c.v = new([]T)
+	c.reset()
+	return &c
+}
+
+func (e *ndenumf) clone() flag.Value {
+	return &ndenumf{ndv: e.ndv.clone().(*ndv[string]), choices: e.choices}
+}
+
+func (e *zvenumf) clone() flag.Value {
+	return &zvenumf{zvv: e.zvv.clone().(*zvv[string]), choices: e.choices}
+}
+
+func (c *ndcountf) clone() flag.Value {
+	return &ndcountf{cv: new(*int)}
+}
+
+func (c *zvcountf) clone() flag.Value {
+	return &zvcountf{cv: new(int)}
+}
+
+func (m *ndsmf) clone() flag.Value {
+	return &ndsmf{mv: new(*map[string]string), opts: m.opts}
+}
+
+func (m *zvsmf) clone() flag.Value {
+	c := &zvsmf{mv: new(map[string]string), opts: m.opts}
+	c.reset()
+	return c
+}
+
+// Clone - returns a new flag set named name with the same flags, aliases,
+// env prefix and variables, required flags, validators, transforms,
+// callbacks, deprecations, exclusive groups and usage settings as ndf,
+// for when you want the same schema parsed into independent values, e.g.
+// per request.  Only the definitions are copied: every flag in the clone
+// starts unset with targets of its own, whatever ndf has parsed so far.
+// Get at the clone's values with Lookup or the getters, since the
+// pointers returned when ndf was built still point at ndf's targets.
+// Values of your own registered via Var can't be copied, so the clone
+// shares them with ndf.  A custom Usage func isn't copied.
+func (ndf *NDFlagSet) Clone(name string) *NDFlagSet {
+	c := NewNDFlagSet(name, ndf.ErrorHandling())
+	if ndf.output != nil {
+		c.SetOutput(ndf.output)
+	}
+	c.copyDefs(ndf, true)
+	c.envPrefix = ndf.envPrefix
+	c.usageHeader, c.usageFooter = ndf.usageHeader, ndf.usageFooter
+	c.usageOrder = ndf.usageOrder
+	c.noDups, c.prefixMatch, c.valueSep = ndf.noDups, ndf.prefixMatch, ndf.valueSep
+	c.showHidden, c.flexInts, c.gnuStyle = ndf.showHidden, ndf.flexInts, ndf.gnuStyle
+	c.warnFunc, c.helpName = ndf.warnFunc, ndf.helpName
+	c.stdinDash, c.stdin = ndf.stdinDash, ndf.stdin
+	c.parseOnce, c.expandEnv, c.unknown = ndf.parseOnce, ndf.expandEnv, ndf.unknown
+	return c
+}
+
+// Merge - registers every flag of other on ndf, along with their aliases,
+// transforms, callbacks, env variables, required flags, validators,
+// deprecations, hidden flags, usage groups and exclusive groups, e.g. to
+// add a shared set of logging flags to each command's own.  The flags
+// keep their targets, so the pointers returned when other was built see
+// what ndf parses.  Settings of other's as a whole, like the env prefix
+// or usage header, aren't merged.  If any of other's names, aliases
+// included, is already defined on ndf, nothing is merged and the error
+// lists them.
+func (ndf *NDFlagSet) Merge(other *NDFlagSet) error {
+	var clash []string
+	other.VisitAll(func(fl *flag.Flag) {
+		if ndf.Lookup(fl.Name) != nil {
+			clash = append(clash, "-"+fl.Name)
+		}
+	})
+	if len(clash) > 0 {
+		return fmt.Errorf("merge %s: flags already defined: %s", other.name, strings.Join(clash, ", "))
+	}
+	ndf.copyDefs(other, false)
+	return nil
+}
+
+// copyDefs - registers from's flags on ndf, along with everything tied to
+// them: aliases, transforms, callbacks, env variables, required flags,
+// validators, deprecations, hidden flags, usage groups and exclusive
+// groups.  With clone, values that can be are cloned, otherwise ndf
+// shares from's targets.
+func (ndf *NDFlagSet) copyDefs(from *NDFlagSet, clone bool) {
+	// new tracked values by old, so the -no-name half of a negatable
+	// bool can be pointed at our positive flag.
+	fresh := make(map[flag.Value]flag.Value)
+	var negs []*flag.Flag
+	declared := len(ndf.declared)
+	from.visitCanonical(func(fl *flag.Flag) {
+		t, ok := fl.Value.(*trackedValue)
+		if !ok {
+			ndf.FlagSet.Var(fl.Value, fl.Name, fl.Usage)
+			return
+		}
+		if _, ok := t.Value.(*negbf); ok {
+			negs = append(negs, fl)
+			return
+		}
+		v := t.Value
+		if cl, ok := v.(cloner); ok && clone {
+			v = cl.clone()
+		}
+		ndf.Var(v, fl.Name, fl.Usage)
+		nt := ndf.tracked[fl.Name]
+		nt.transforms = append([]func(string) string(nil), t.transforms...)
+		nt.onSet = append(([]func(interface{}))(nil), t.onSet...)
+		fresh[t] = ndf.Lookup(fl.Name).Value
+	})
+	for _, fl := range negs {
+		n := fl.Value.(*trackedValue).Value.(*negbf)
+		ndf.Var(&negbf{pos: fresh[n.pos], example: n.example}, fl.Name, fl.Usage)
+	}
+	// the flags were registered above in lexical order, keep from's.
+	ndf.declared = append(ndf.declared[:declared], from.declared...)
+
+	for alias, canonical := range from.aliases {
+		ndf.Alias(canonical, alias)
+	}
+	for n, env := range from.envVars {
+		ndf.BindEnv(n, env)
+	}
+	ndf.Require(from.required...)
+	for n, fns := range from.validators {
+		for _, fn := range fns {
+			ndf.addValidator(n, fn)
+		}
+	}
+	for n, msg := range from.deprecated {
+		ndf.Deprecate(n, msg)
+	}
+	for n := range from.hidden {
+		ndf.Hide(n)
+	}
+	for i, g := range from.groups {
+		var names []string
+		for _, n := range g.names {
+			if from.groupOf[n] == i {
+				names = append(names, n)
+			}
+		}
+		ndf.Group(g.title, names...)
+	}
+	for _, group := range from.exclusive {
+		ndf.MutuallyExclusive(append([]string(nil), group...)...)
+	}
+}
@@ -0,0 +1,98 @@
+package nodefflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	name := fs.NDString("name", "", "name")
+	port := fs.ZVInt("port", 0, "port")
+	tags := fs.ZVStringSlice("tag", "tags")
+	debug := fs.NDBoolNegatable("debug", false, "debug")
+	fs.Alias("name", "n")
+	fs.Require("port")
+	if err := fs.Parse([]string{"-n", "orig", "-port", "1", "-tag", "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fs.Clone("clone")
+	if c.Name() != "clone" {
+		t.Errorf("bad name %q", c.Name())
+	}
+	if c.IsSet("name") || c.IsSet("port") {
+		t.Error("clone should start unset")
+	}
+	if v, _ := c.StringValue("name"); v != "" {
+		t.Errorf("clone name should be unset, got %q", v)
+	}
+	if err := c.CheckRequired(); err == nil || !strings.Contains(err.Error(), "-port") {
+		t.Errorf("clone should require -port, got %v", err)
+	}
+
+	if err := c.Parse([]string{"-n", "clone", "-port", "2", "-tag", "b", "-no-debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.StringValue("name"); v != "clone" {
+		t.Errorf("clone name: got %q", v)
+	}
+	if v, _ := c.IntValue("port"); v != 2 {
+		t.Errorf("clone port: got %d", v)
+	}
+	if v, ok := c.BoolValue("debug"); !ok || v {
+		t.Errorf("clone debug: got %v, %v", v, ok)
+	}
+	if **name != "orig" || *port != 1 || len(*tags) != 1 || (*tags)[0] != "a" || *debug != nil {
+		t.Errorf("original changed: %q %d %q %v", **name, *port, *tags, *debug)
+	}
+	if fs.IsSet("debug") {
+		t.Error("original -debug should be unset")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	logging := NewNDFlagSet("logging", flag.ContinueOnError)
+	level := logging.NDString("log-level", "info", "log level")
+	logging.Alias("log-level", "l")
+	color := logging.NDBoolNegatable("color", true, "color output")
+	logging.Require("log-level")
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	port := fs.ZVInt("port", 0, "port")
+	if err := fs.Merge(logging); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-port=1", "-l=debug", "-no-color"}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 1 {
+		t.Errorf("bad port: %d", *port)
+	}
+	if *level == nil || **level != "debug" {
+		t.Errorf("merged flag should share its target, got %v", *level)
+	}
+	if *color == nil || **color || !fs.IsSet("color") {
+		t.Errorf("-no-color should unset the merged -color, got %v", *color)
+	}
+	if err := fs.CheckRequired(); err != nil {
+		t.Errorf("required flags should be merged too: %v", err)
+	}
+	if logging.IsSet("log-level") {
+		t.Errorf("parsed state shouldn't show up in the merged set")
+	}
+
+	other := NewNDFlagSet("other", flag.ContinueOnError)
+	other.NDInt("port", 0, "port")
+	other.NDString("extra", "", "extra")
+	other.NDString("level", "", "level")
+	other.Alias("level", "l")
+	err := fs.Merge(other)
+	if err == nil || err.Error() != "merge other: flags already defined: -l, -port" {
+		t.Errorf("expected a collision error, got %v", err)
+	}
+	if fs.Lookup("extra") != nil {
+		t.Errorf("nothing should be merged on a collision")
+	}
+}
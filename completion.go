@@ -0,0 +1,57 @@
+package nodefflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var nonIdent = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// BashCompletion - writes a bash completion script for prog, completing
+// every flag name, aliases included, and the choices of enum flags after
+// the flag.  Anything else falls back to file name completion.  Source it
+// from your .bashrc, or drop it in the bash-completion directory.
+func (ndf *NDFlagSet) BashCompletion(prog string, w io.Writer) error {
+	prog = filepath.Base(prog)
+	fn := "_" + nonIdent.ReplaceAllString(prog, "_") + "_complete"
+
+	var names []string
+	var cases bytes.Buffer
+	ndf.VisitAll(func(fl *flag.Flag) {
+		if ndf.isHidden(fl.Name) {
+			return
+		}
+		names = append(names, "-"+fl.Name)
+		if c, ok := unwrapValue(fl.Value).(choiceLister); ok {
+			fmt.Fprintf(&cases, "\t-%s)\n\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n\t\treturn\n\t\t;;\n",
+				fl.Name, shellQuote(strings.Join(c.choiceList(), " ")))
+		}
+	})
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# bash completion for %s\n", prog)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	if cases.Len() > 0 {
+		b.WriteString("\tcase \"$prev\" in\n")
+		b.Write(cases.Bytes())
+		b.WriteString("\tesac\n")
+	}
+	b.WriteString("\tif [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(names, " ")))
+	b.WriteString("\tfi\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, shellQuote(prog))
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// shellQuote - single quotes s for the shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
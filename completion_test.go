@@ -0,0 +1,36 @@
+package nodefflag
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestBashCompletion(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("port", 0, "port")
+	fs.ZVString("name", "", "name")
+	fs.NDBoolNegatable("cache", false, "cache")
+	fs.NDEnum("mode", []string{"fast", "safe"}, "mode")
+	fs.Alias("port", "p")
+
+	var buf bytes.Buffer
+	if err := fs.BashCompletion("/usr/local/bin/my-tool", &buf); err != nil {
+		t.Fatal(err)
+	}
+	script := buf.String()
+
+	if !strings.Contains(script, `compgen -W '-cache -mode -name -no-cache -p -port' -- "$cur"`) {
+		t.Errorf("flag names missing:\n%s", script)
+	}
+	if !strings.Contains(script, "\t-mode)\n\t\tCOMPREPLY=($(compgen -W 'fast safe' -- \"$cur\"))") {
+		t.Errorf("enum choices missing:\n%s", script)
+	}
+	if !strings.HasSuffix(script, "complete -o default -F _my_tool_complete 'my-tool'\n") {
+		t.Errorf("bad complete line:\n%s", script)
+	}
+	if !strings.Contains(script, "_my_tool_complete() {\n") {
+		t.Errorf("bad function name:\n%s", script)
+	}
+}
@@ -0,0 +1,139 @@
+package nodefflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// ParseWithConfig - parses args, then any flag still unset is set from
+// the JSON object in configPath, keyed by flag name.  Precedence is
+// command line, then config, then unset; an ND flag's double pointer only
+// becomes non-nil when the config actually has the key.
+//
+// Values are converted to strings and passed to the flag's Set: strings
+// as is, numbers and bools as written, objects as raw JSON.  Arrays call
+// Set once per element, which suits the repeatable slice flags.  null is
+// ignored.  Keys that don't match a flag are an error, as is a missing or
+// malformed config file.
+func (ndf *NDFlagSet) ParseWithConfig(args []string, configPath string) error {
+	if err := ndf.parseArgs(args); err != nil {
+		return err
+	}
+	if err := ndf.applyConfig(configPath); err != nil {
+		return ndf.fail(err)
+	}
+	return ndf.postParse()
+}
+
+func (ndf *NDFlagSet) applyConfig(configPath string) error {
+	b, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	var cfg map[string]json.RawMessage
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return fmt.Errorf("config %s: %v", configPath, err)
+	}
+
+	keys := make([]string, 0, len(cfg))
+	for k := range cfg {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fl := ndf.Lookup(k)
+		if fl == nil {
+			return fmt.Errorf("config %s: unknown flag %q", configPath, k)
+		}
+		if ndf.IsSet(k) {
+			continue
+		}
+		vals, err := configStrings(cfg[k])
+		if err != nil {
+			return fmt.Errorf("config %s: bad value for flag -%s: %v", configPath, k, err)
+		}
+		for _, v := range vals {
+			if err := ndf.Set(k, v); err != nil {
+				return fmt.Errorf("config %s: invalid value %q for flag -%s: %v", configPath, v, k, err)
+			}
+		}
+	}
+	return nil
+}
+
+// configStrings - converts a JSON value into the strings to Set.
+func configStrings(raw json.RawMessage) ([]string, error) {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		var out []string
+		for _, e := range v {
+			b, err := json.Marshal(e)
+			if err != nil {
+				return nil, err
+			}
+			s, err := configStrings(b)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s...)
+		}
+		return out, nil
+	case map[string]interface{}:
+		return []string{string(raw)}, nil
+	case string:
+		return []string{v}, nil
+	case json.Number:
+		return []string{v.String()}, nil
+	case bool:
+		return []string{strconv.FormatBool(v)}, nil
+	}
+	return nil, fmt.Errorf("unsupported JSON value %s", raw)
+}
+
+// ApplyMap - sets flags from values, keyed by flag name, e.g. for config
+// from somewhere ParseWithConfig doesn't cover.  Flags that are already
+// set, whether on the command line or by an earlier ApplyMap, are
+// skipped, so apply the layers highest precedence first.  An ND flag's
+// double pointer only becomes non-nil if its key is applied.  With strict,
+// keys that don't match a flag are an error, otherwise they're ignored.
+// Every problem is reported, one per line, rather than just the first,
+// and the flags that could be set still are.  Unlike the Parse methods it
+// just returns the error, without printing it or honoring ErrorHandling.
+func (ndf *NDFlagSet) ApplyMap(values map[string]string, strict bool) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, k := range keys {
+		if ndf.Lookup(k) == nil {
+			if strict {
+				errs = append(errs, fmt.Errorf("unknown flag %q", k))
+			}
+			continue
+		}
+		if ndf.IsSet(k) {
+			continue
+		}
+		if err := ndf.Set(k, values[k]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value %q for flag -%s: %v", values[k], k, err))
+		}
+	}
+	return errors.Join(errs...)
+}
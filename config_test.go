@@ -0,0 +1,162 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	p := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(p, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestParseWithConfig(t *testing.T) {
+	cfg := writeConfig(t, `{
+		"name": "from config",
+		"port": 8080,
+		"debug": true,
+		"ratio": 0.5,
+		"tag": ["a", "b"],
+		"cli_wins": "from config",
+		"zv_int": 3,
+		"nothing": null
+	}`)
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	name := fs.NDString("name", "", "name")
+	port := fs.NDInt("port", 0, "port")
+	debug := fs.NDBool("debug", false, "debug")
+	ratio := fs.NDFloat64("ratio", 0, "ratio")
+	tag := fs.NDStringSlice("tag", "tags")
+	cliWins := fs.NDString("cli_wins", "", "cli wins")
+	zvInt := fs.ZVInt("zv_int", 0, "int")
+	nothing := fs.NDString("nothing", "", "null in the config")
+	absent := fs.NDString("absent", "", "not in the config")
+
+	if err := fs.ParseWithConfig([]string{"-cli_wins=from cli"}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if *name == nil || **name != "from config" {
+		t.Errorf("bad name: %v", *name)
+	}
+	if *port == nil || **port != 8080 {
+		t.Errorf("bad port: %v", *port)
+	}
+	if *debug == nil || !**debug {
+		t.Errorf("bad debug: %v", *debug)
+	}
+	if *ratio == nil || **ratio != 0.5 {
+		t.Errorf("bad ratio: %v", *ratio)
+	}
+	if *tag == nil || fmt.Sprint(**tag) != "[a b]" {
+		t.Errorf("bad tag: %v", *tag)
+	}
+	if **cliWins != "from cli" {
+		t.Errorf("the command line should beat the config: %s", **cliWins)
+	}
+	if *zvInt != 3 || !fs.IsSet("zv_int") {
+		t.Errorf("bad zv_int: %d", *zvInt)
+	}
+	if *nothing != nil || *absent != nil {
+		t.Error("null and absent keys should leave flags unset")
+	}
+}
+
+func TestParseWithConfigDeprecated(t *testing.T) {
+	cfg := writeConfig(t, `{"old": "a", "legacy": "b"}`)
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	out := &strings.Builder{}
+	fs.SetOutput(out)
+	fs.NDString("new", "", "new name")
+	fs.Alias("new", "old")
+	fs.NDString("legacy", "", "legacy")
+	fs.NDString("other", "", "other")
+	fs.Deprecate("old", "use -new")
+	fs.Deprecate("legacy", "drop it")
+	fs.Deprecate("other", "unused")
+
+	if err := fs.ParseWithConfig(nil, cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := "flag -legacy is deprecated: drop it\nflag -old is deprecated: use -new\n"
+	if out.String() != want {
+		t.Errorf("config keys should warn by the name used, got %q", out.String())
+	}
+}
+
+func TestParseWithConfigErrors(t *testing.T) {
+	newFS := func() *NDFlagSet {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.NDInt("port", 0, "port")
+		return fs
+	}
+
+	tests := []struct {
+		name, path, want string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "nope.json"), "no such file"},
+		{"malformed", writeConfig(t, `{"port": `), "unexpected end"},
+		{"unknown key", writeConfig(t, `{"prot": 1}`), `unknown flag "prot"`},
+		{"bad value", writeConfig(t, `{"port": "http"}`), `invalid value "http" for flag -port`},
+	}
+	for _, tt := range tests {
+		err := newFS().ParseWithConfig(nil, tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestApplyMap(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	host := fs.NDString("host", "", "host")
+	port := fs.NDInt("port", 0, "port")
+	user := fs.NDString("user", "", "user")
+	workers := fs.ZVInt("workers", 0, "workers")
+
+	if err := fs.Parse([]string{"-host=cli"}); err != nil {
+		t.Fatal(err)
+	}
+	err := fs.ApplyMap(map[string]string{"host": "map", "port": "5432", "nope": "x"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if **host != "cli" {
+		t.Errorf("flag set on the command line was overwritten: %q", **host)
+	}
+	if *port == nil || **port != 5432 {
+		t.Errorf("bad port: %v", *port)
+	}
+	if *user != nil {
+		t.Error("user wasn't in the map, should stay nil")
+	}
+
+	// a later layer doesn't override an earlier one
+	err = fs.ApplyMap(map[string]string{"port": "1", "user": "bob"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if **port != 5432 || **user != "bob" {
+		t.Errorf("bad values after second layer: %d %q", **port, **user)
+	}
+
+	err = fs.ApplyMap(map[string]string{"workers": "x", "nope": "1", "other": "2"}, true)
+	want := `unknown flag "nope"
+unknown flag "other"
+invalid value "x" for flag -workers: strconv.Atoi: parsing "x": invalid syntax`
+	if err == nil || err.Error() != want {
+		t.Errorf("bad error, got:\n%v\nwant:\n%s", err, want)
+	}
+	if *workers != 0 || fs.IsSet("workers") {
+		t.Error("workers should be unset")
+	}
+}
@@ -0,0 +1,41 @@
+package nodefflag
+
+import (
+	"flag"
+	"strings"
+)
+
+// Deprecate - marks the named flag as deprecated.  It keeps working, but
+// when it's set Parse writes a warning to the output, e.g. Deprecate("old",
+// "use -new instead") warns
+//
+//	flag -old is deprecated: use -new instead
+func (ndf *NDFlagSet) Deprecate(name, message string) {
+	if ndf.deprecated == nil {
+		ndf.deprecated = make(map[string]string)
+	}
+	ndf.deprecated[name] = message
+}
+
+// warnDeprecated - warns about the deprecated names that were used.  It
+// goes by the names the embedded FlagSet saw being set rather than
+// IsSet, which an alias shares with its flag, so deprecating the old name
+// of a renamed flag doesn't warn about uses of the new one.  A value from
+// another source only counts if it was set through Set, which records the
+// name.
+func (ndf *NDFlagSet) warnDeprecated() {
+	ndf.FlagSet.Visit(func(fl *flag.Flag) {
+		ndf.warnIfDeprecated(fl.Name)
+	})
+}
+
+// warnIfDeprecated - warns about name if it's deprecated, or the -no-
+// half of a deprecated negatable bool.
+func (ndf *NDFlagSet) warnIfDeprecated(name string) {
+	if pos := strings.TrimPrefix(name, "no-"); pos != name && ndf.negationOf(pos) == name {
+		name = pos
+	}
+	if msg, ok := ndf.deprecated[name]; ok {
+		ndf.warnf("flag -%s is deprecated: %s", name, msg)
+	}
+}
@@ -0,0 +1,96 @@
+package nodefflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"testing"
+)
+
+func TestDeprecate(t *testing.T) {
+	newFS := func() (*NDFlagSet, *bytes.Buffer, **string) {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		buf := &bytes.Buffer{}
+		fs.SetOutput(buf)
+		old := fs.NDString("old", "", "old name")
+		fs.NDString("new", "", "new name")
+		fs.Deprecate("old", "use -new instead")
+		return fs, buf, old
+	}
+
+	fs, buf, old := newFS()
+	if err := fs.Parse([]string{"-old=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "flag -old is deprecated: use -new instead\n"; buf.String() != want {
+		t.Errorf("bad warning: %q", buf)
+	}
+	if *old == nil || **old != "x" {
+		t.Error("deprecated flag should still work")
+	}
+
+	fs, buf, _ = newFS()
+	if err := fs.Parse([]string{"-new=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected warning: %q", buf)
+	}
+}
+
+func TestDeprecateAlias(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	buf := &bytes.Buffer{}
+	fs.SetOutput(buf)
+	name := fs.NDString("new", "", "new name")
+	fs.Alias("new", "old")
+	fs.Deprecate("old", "use -new")
+
+	if err := fs.Parse([]string{"-new=a"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("using the canonical name should not warn: %q", buf)
+	}
+
+	fs.Reset()
+	if err := fs.Parse([]string{"-old=b"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "flag -old is deprecated: use -new\n"; buf.String() != want {
+		t.Errorf("bad warning: %q", buf)
+	}
+	if **name != "b" {
+		t.Errorf("the alias should still set the flag, got %q", **name)
+	}
+}
+
+func TestSetWarnFunc(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	buf := &bytes.Buffer{}
+	fs.SetOutput(buf)
+	fs.NDString("old", "", "old name")
+	fs.Deprecate("old", "use -new instead")
+
+	var got []string
+	fs.SetWarnFunc(func(format string, args ...interface{}) {
+		got = append(got, fmt.Sprintf(format, args...))
+	})
+	if err := fs.Parse([]string{"-old=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "flag -old is deprecated: use -new instead" {
+		t.Errorf("bad warnings: %q", got)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should go to the output: %q", buf)
+	}
+
+	fs.SetWarnFunc(nil)
+	if err := fs.Parse([]string{"-old=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "flag -old is deprecated: use -new instead\n"; buf.String() != want {
+		t.Errorf("the default should write to the output: %q", buf)
+	}
+}
@@ -0,0 +1,50 @@
+package nodefflag
+
+import (
+	"flag"
+	"io"
+)
+
+// DryRunParse - parses args into a Clone of the flag set rather than ndf
+// itself, so the pointers handed out by ndf are left alone, and reports
+// what would be set: the argument each flag would be set from, keyed by
+// its canonical name.  For repeated flags that's the last one, and a
+// -no-name shows up as name set to false.  Flags that would be set from
+// their env variable are included.  Validators and the other checks run
+// as for Parse, but errors are just returned, nothing is printed or
+// warned, OnSet callbacks aren't called, and the ErrorHandling is
+// ignored.  Values of your own registered via Var are shared with the
+// clone, see Clone, so those still get set.
+func (ndf *NDFlagSet) DryRunParse(args []string) (map[string]string, error) {
+	c := ndf.Clone(ndf.name)
+	c.Init(ndf.name, flag.ContinueOnError)
+	c.SetOutput(io.Discard)
+	c.SetWarnFunc(nil)
+
+	raw := make(map[string]string)
+	c.visitCanonical(func(fl *flag.Flag) {
+		name := fl.Name
+		if t, ok := c.tracked[name]; ok {
+			t.onSet = nil
+			t.transforms = append(t.transforms, func(val string) string {
+				raw[name] = val
+				return val
+			})
+		}
+	})
+	if err := c.Parse(args); err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]string)
+	c.visitCanonical(func(fl *flag.Flag) {
+		t, ok := c.tracked[fl.Name]
+		if !ok || t.count == 0 {
+			return
+		}
+		if _, ok := t.Value.(*negbf); !ok {
+			set[fl.Name] = raw[fl.Name]
+		}
+	})
+	return set, nil
+}
@@ -0,0 +1,43 @@
+package nodefflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestDryRunParse(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	name := fs.NDString("name", "", "name")
+	port := fs.ZVInt("port", 0, "port")
+	tags := fs.NDStringSlice("tag", "tags")
+	cache := fs.NDBoolNegatable("cache", true, "cache")
+	fs.NDString("unset", "", "never given")
+	fs.Alias("name", "n")
+	fs.MutuallyExclusive("name", "unset")
+
+	got, err := fs.DryRunParse([]string{"-n=x", "-port=8", "-tag=a", "-tag=b", "-no-cache"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"name": "x", "port": "8", "tag": "b", "cache": "false"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if *name != nil || *port != 0 || *tags != nil || *cache != nil {
+		t.Errorf("pointers should be untouched: %v %d %v %v", *name, *port, *tags, *cache)
+	}
+	if fs.IsSet("name") || fs.IsSet("port") {
+		t.Errorf("flags shouldn't count as set")
+	}
+
+	if _, err := fs.DryRunParse([]string{"-port=x"}); err == nil {
+		t.Errorf("expected an error for a bad value")
+	}
+	if _, err := fs.DryRunParse([]string{"-name=x", "-unset=y"}); err == nil {
+		t.Errorf("expected the exclusive check to run")
+	}
+	if *port != 0 {
+		t.Errorf("a failed dry run shouldn't touch the pointers either")
+	}
+}
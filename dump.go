@@ -0,0 +1,69 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dump - dumps every flag with its current value, one per line and
+// sorted by name, for -debug-flags style output or test assertions:
+//
+//	-name: "x" (set)
+//	-port: <unset>
+//	-workers: 0 (not set)
+//
+// ND flags that weren't given show <unset>, everything else shows the
+// current value and whether it was set.  Aliases aren't listed.  It's not
+// called String so the embedded FlagSet's String, which defines a flag,
+// stays reachable.
+func (ndf *NDFlagSet) Dump() string {
+	var b strings.Builder
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		var v interface{}
+		if g, ok := fl.Value.(flag.Getter); ok {
+			v = g.Get()
+		} else {
+			v = fl.Value.String()
+		}
+		val, ok := dumpValue(v)
+		switch {
+		case !ok && ndf.IsSet(fl.Name):
+			// nothing to show, e.g. NDFunc
+			fmt.Fprintf(&b, "-%s: (set)\n", fl.Name)
+		case !ok:
+			fmt.Fprintf(&b, "-%s: <unset>\n", fl.Name)
+		case ndf.IsSet(fl.Name):
+			fmt.Fprintf(&b, "-%s: %s (set)\n", fl.Name, val)
+		default:
+			fmt.Fprintf(&b, "-%s: %s (not set)\n", fl.Name, val)
+		}
+	})
+	return b.String()
+}
+
+// dumpValue - formats a Get() result for Dump, false if it's a nil
+// pointer.  Strings are quoted so empty ones show up.
+func dumpValue(v interface{}) (string, bool) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return "", false
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "", false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.String {
+		return fmt.Sprintf("%q", rv.String()), true
+	}
+	// String methods often have pointer receivers, call them on a copy.
+	p := reflect.New(rv.Type())
+	p.Elem().Set(rv)
+	if s, ok := p.Interface().(fmt.Stringer); ok {
+		return s.String(), true
+	}
+	return fmt.Sprint(rv.Interface()), true
+}
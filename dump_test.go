@@ -0,0 +1,63 @@
+package nodefflag
+
+import (
+	"flag"
+	"net"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("name", "", "name")
+	fs.NDInt("port", 0, "port")
+	fs.NDDuration("timeout", 0, "timeout")
+	fs.NDIPNet("net", net.IPNet{}, "network")
+	fs.ZVInt("workers", 0, "workers")
+	fs.ZVString("zone", "", "zone")
+	fs.ZVIntDefault("retries", 3, "retries")
+	fs.ZVStringSlice("tag", "tags")
+	fs.Alias("name", "n")
+	fs.NDFunc("hook", "hook", func(string) error { return nil })
+
+	want := `-hook: <unset>
+-name: <unset>
+-net: <unset>
+-port: <unset>
+-retries: 3 (not set)
+-tag: [] (not set)
+-timeout: <unset>
+-workers: 0 (not set)
+-zone: "" (not set)
+`
+	if got := fs.Dump(); got != want {
+		t.Errorf("bad dump before parse, got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := fs.Parse([]string{"-n", "", "-timeout=1m", "-zone=eu", "-tag=a", "-tag=b", "-net=10.1.0.0/16", "-hook=x"}); err != nil {
+		t.Fatal(err)
+	}
+	want = `-hook: (set)
+-name: "" (set)
+-net: 10.1.0.0/16 (set)
+-port: <unset>
+-retries: 3 (not set)
+-tag: [a b] (set)
+-timeout: 1m0s (set)
+-workers: 0 (not set)
+-zone: "eu" (set)
+`
+	if got := fs.Dump(); got != want {
+		t.Errorf("bad dump, got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDumpKeepsFlagSetString(t *testing.T) {
+	fs := NewNDFlagSet("test", flag.ContinueOnError)
+	name := fs.String("name", "bob", "a plain string flag")
+	if err := fs.Parse([]string{"-name=alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "alice" {
+		t.Errorf("bad name, got %q", *name)
+	}
+}
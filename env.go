@@ -0,0 +1,284 @@
+package nodefflag
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SetEnvPrefix - sets the prefix ParseWithEnv uses to build environment
+// variable names.  The prefix is used as is, so include any separator,
+// e.g. "MYAPP_".
+func (ndf *NDFlagSet) SetEnvPrefix(prefix string) {
+	ndf.envPrefix = prefix
+}
+
+// EnvName - returns the environment variable ParseWithEnv consults for
+// the named flag: the env prefix followed by the flag name uppercased,
+// with dashes and dots turned into underscores.  With a prefix of
+// "MYAPP_", -log-level maps to MYAPP_LOG_LEVEL.
+func (ndf *NDFlagSet) EnvName(name string) string {
+	return envName(ndf.envPrefix, name)
+}
+
+func envName(prefix, name string) string {
+	return prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// ParseWithEnv - parses args, then any flag still unset is set from its
+// environment variable (see EnvName), if that exists.  Precedence is
+// command line, then environment, then unset; an ND flag's double pointer
+// only becomes non-nil when the variable is actually present, even if
+// it's empty.
+func (ndf *NDFlagSet) ParseWithEnv(args []string) error {
+	if err := ndf.parseArgs(args); err != nil {
+		return err
+	}
+	if err := ndf.applyEnv(); err != nil {
+		return ndf.fail(err)
+	}
+	return ndf.postParse()
+}
+
+func (ndf *NDFlagSet) applyEnv() error {
+	var err error
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		if err != nil || ndf.IsSet(fl.Name) {
+			return
+		}
+		env := ndf.EnvName(fl.Name)
+		val, ok := os.LookupEnv(env)
+		if !ok {
+			return
+		}
+		if serr := ndf.Set(fl.Name, val); serr != nil {
+			err = fmt.Errorf("invalid value %q for env %s (flag -%s): %v", val, env, fl.Name, serr)
+		}
+	})
+	return err
+}
+
+// ParseWithEnvFile - parses args, then any flag still unset is set from
+// the dotenv style file at path, see ParseEnvFile, then the validators
+// and other checks run as for Parse.
+func (ndf *NDFlagSet) ParseWithEnvFile(args []string, path string) error {
+	if err := ndf.parseArgs(args); err != nil {
+		return err
+	}
+	if _, err := ndf.applyEnvFile(path); err != nil {
+		return ndf.fail(err)
+	}
+	return ndf.postParse()
+}
+
+// ParseEnvFile - sets flags that are still unset from the dotenv style
+// file at path, so call it after Parse or ParseWithEnv, which win.  Keys
+// are matched against EnvName, so with a prefix of "MYAPP_" the key
+// MYAPP_LOG_LEVEL sets -log-level; keys that don't match a flag are
+// ignored, as the file usually has other settings too.  An ND flag's
+// double pointer only becomes non-nil if its key is in the file.  Once
+// applied, the flags it set are validated and warned about if deprecated,
+// the rest already were by Parse, and the mutually exclusive and
+// duplicate checks run again.  ParseWithEnvFile does it all in one go.
+//
+// The file has one KEY=value per line, optionally preceded by export.
+// Blank lines and lines starting with # are skipped.  Values may be
+// 'single quoted', taken as is, or "double quoted", where \n, \" and \\
+// are unescaped.  Unquoted values are trimmed, and end at a " #" comment.
+func (ndf *NDFlagSet) ParseEnvFile(path string) error {
+	set, err := ndf.applyEnvFile(path)
+	if err != nil {
+		return ndf.fail(err)
+	}
+	var errs []error
+	for _, name := range set {
+		ndf.warnIfDeprecated(name)
+		errs = append(errs, ndf.validateFlag(ndf.Lookup(name))...)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return ndf.fail(err)
+	}
+	if err := ndf.checkExclusive(); err != nil {
+		return ndf.fail(err)
+	}
+	if err := ndf.checkDuplicates(); err != nil {
+		return ndf.fail(err)
+	}
+	return nil
+}
+
+// applyEnvFile - sets the unset flags from the file at path, returning
+// the names of those it set, sorted.
+func (ndf *NDFlagSet) applyEnvFile(path string) ([]string, error) {
+	vals, err := readEnvFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set []string
+	byEnv := make(map[string]*flag.Flag)
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		if _, ok := byEnv[ndf.EnvName(fl.Name)]; !ok {
+			byEnv[ndf.EnvName(fl.Name)] = fl
+		}
+	})
+	for _, kv := range vals {
+		fl, ok := byEnv[kv[0]]
+		if !ok || ndf.IsSet(fl.Name) {
+			continue
+		}
+		if err := ndf.Set(fl.Name, kv[1]); err != nil {
+			return nil, fmt.Errorf("%s: invalid value %q for %s (flag -%s): %v", path, kv[1], kv[0], fl.Name, err)
+		}
+		set = append(set, fl.Name)
+	}
+	sort.Strings(set)
+	return set, nil
+}
+
+// readEnvFile - the key, value pairs of a dotenv file, in file order.
+func readEnvFile(path string) ([][2]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var vals [][2]string
+	sc := bufio.NewScanner(f)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("%s:%d: expected KEY=value", path, lineNo)
+		}
+		key, val := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch {
+		case len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'':
+			val = val[1 : len(val)-1]
+		case len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"':
+			val = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(val[1 : len(val)-1])
+		default:
+			if j := strings.Index(val, " #"); j >= 0 {
+				val = strings.TrimSpace(val[:j])
+			}
+		}
+		vals = append(vals, [2]string{key, val})
+	}
+	return vals, sc.Err()
+}
+
+// BindEnv - ties the named flag to the environment variable envVar, used
+// as is rather than built from the prefix, e.g. for legacy names.  After
+// any of the Parse methods, if the flag is still unset and envVar exists,
+// the flag is set from it.  For the common types see NDStringEnv and co.
+func (ndf *NDFlagSet) BindEnv(name, envVar string) {
+	if ndf.envVars == nil {
+		ndf.envVars = make(map[string]string)
+	}
+	ndf.envVars[name] = envVar
+}
+
+func (ndf *NDFlagSet) applyEnvVars() error {
+	var err error
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		env, ok := ndf.envVars[fl.Name]
+		if err != nil || !ok || ndf.IsSet(fl.Name) {
+			return
+		}
+		val, ok := os.LookupEnv(env)
+		if !ok {
+			return
+		}
+		if serr := ndf.Set(fl.Name, val); serr != nil {
+			err = fmt.Errorf("invalid value %q for env %s (flag -%s): %v", val, env, fl.Name, serr)
+		}
+	})
+	return err
+}
+
+// SetExpandEnv - with on, values of the string and path flags go through
+// os.ExpandEnv as they're set, so -dir=$HOME/data gets the resolved path,
+// and transforms and the path checks see it.  Unset variables expand to
+// "".  Off by default, since a $ in a value like a password is usually
+// meant literally, and shells already expand unquoted arguments.
+func (ndf *NDFlagSet) SetExpandEnv(on bool) {
+	ndf.expandEnv = on
+}
+
+// expandString - val with env variables expanded, if ndf expands them
+// and v is a string or path flag.
+func (ndf *NDFlagSet) expandString(v flag.Value, val string) string {
+	if !ndf.expandEnv {
+		return val
+	}
+	switch v.(type) {
+	case *ndv[string], *zvv[string]:
+		return os.ExpandEnv(val)
+	}
+	return val
+}
+
+// NDStringEnv - NDString, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) NDStringEnv(name, envVar, example, usage string) **string {
+	p := ndf.NDString(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
+
+// NDBoolEnv - NDBool, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) NDBoolEnv(name, envVar string, example bool, usage string) **bool {
+	p := ndf.NDBool(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
+
+// NDIntEnv - NDInt, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) NDIntEnv(name, envVar string, example int, usage string) **int {
+	p := ndf.NDInt(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
+
+// NDDurationEnv - NDDuration, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) NDDurationEnv(name, envVar string, example time.Duration, usage string) **time.Duration {
+	p := ndf.NDDuration(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
+
+// ZVStringEnv - ZVString, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) ZVStringEnv(name, envVar, example, usage string) *string {
+	p := ndf.ZVString(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
+
+// ZVBoolEnv - ZVBool, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) ZVBoolEnv(name, envVar string, example bool, usage string) *bool {
+	p := ndf.ZVBool(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
+
+// ZVIntEnv - ZVInt, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) ZVIntEnv(name, envVar string, example int, usage string) *int {
+	p := ndf.ZVInt(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
+
+// ZVDurationEnv - ZVDuration, falling back to envVar, see BindEnv
+func (ndf *NDFlagSet) ZVDurationEnv(name, envVar string, example time.Duration, usage string) *time.Duration {
+	p := ndf.ZVDuration(name, example, usage)
+	ndf.BindEnv(name, envVar)
+	return p
+}
@@ -0,0 +1,313 @@
+package nodefflag
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setenv(t *testing.T, key, val string) {
+	if err := os.Setenv(key, val); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseWithEnv(t *testing.T) {
+	setenv(t, "NDTEST_ENV_ONLY", "from env")
+	setenv(t, "NDTEST_CLI_WINS", "from env")
+	setenv(t, "NDTEST_EMPTY", "")
+	setenv(t, "NDTEST_ZV_INT", "7")
+	defer func() {
+		for _, k := range []string{"NDTEST_ENV_ONLY", "NDTEST_CLI_WINS", "NDTEST_EMPTY", "NDTEST_ZV_INT"} {
+			os.Unsetenv(k)
+		}
+	}()
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetEnvPrefix("NDTEST_")
+	envOnly := fs.NDString("env-only", "", "string value")
+	cliWins := fs.NDString("cli.wins", "", "string value")
+	empty := fs.NDString("empty", "", "string value")
+	absent := fs.NDString("absent", "", "string value")
+	zvInt := fs.ZVInt("zv_int", 0, "int value")
+
+	if n := fs.EnvName("env-only"); n != "NDTEST_ENV_ONLY" {
+		t.Errorf("bad env name: %s", n)
+	}
+
+	if err := fs.ParseWithEnv([]string{"-cli.wins=from cli"}); err != nil {
+		t.Fatal(err)
+	}
+	if *envOnly == nil || **envOnly != "from env" {
+		t.Error("expected env-only to come from the environment")
+	}
+	if *cliWins == nil || **cliWins != "from cli" {
+		t.Error("expected the command line to beat the environment")
+	}
+	if *empty == nil || **empty != "" {
+		t.Error("expected an empty env var to still set the flag")
+	}
+	if *absent != nil {
+		t.Error("expected absent to stay unset")
+	}
+	if *zvInt != 7 || !fs.IsSet("zv_int") {
+		t.Error("expected zv_int to come from the environment")
+	}
+}
+
+func TestParseWithEnvDeprecated(t *testing.T) {
+	setenv(t, "NDTEST_LEGACY", "x")
+	defer os.Unsetenv("NDTEST_LEGACY")
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	out := &strings.Builder{}
+	fs.SetOutput(out)
+	fs.SetEnvPrefix("NDTEST_")
+	fs.NDString("legacy", "", "legacy")
+	fs.NDString("other", "", "other")
+	fs.Deprecate("legacy", "drop it")
+	fs.Deprecate("other", "unused")
+
+	if err := fs.ParseWithEnv(nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "flag -legacy is deprecated: drop it\n"; out.String() != want {
+		t.Errorf("setting from env should warn, got %q", out.String())
+	}
+}
+
+func TestParseWithEnvBadValue(t *testing.T) {
+	setenv(t, "NDTEST_BAD_INT", "nope")
+	defer os.Unsetenv("NDTEST_BAD_INT")
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.SetEnvPrefix("NDTEST_")
+	fs.NDInt("bad-int", 0, "int value")
+
+	if err := fs.ParseWithEnv(nil); err == nil {
+		t.Error("expected bad env value to fail")
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".env")
+	contents := `# settings for the app
+MYAPP_NAME=from file
+MYAPP_CLI_WINS=from file
+
+export MYAPP_GREETING="hello \"world\"\nbye"
+MYAPP_RAW='no \n escapes # here'
+MYAPP_PORT = 8080 # trailing comment
+MYAPP_LOG_LEVEL=debug
+MYAPP_EMPTY=
+OTHER_TOOL_SETTING=ignored
+MYAPP_UNKNOWN=ignored too
+`
+	if err := os.WriteFile(p, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.SetEnvPrefix("MYAPP_")
+	name := fs.NDString("name", "", "name")
+	cli := fs.NDString("cli-wins", "", "cli wins")
+	greeting := fs.NDString("greeting", "", "greeting")
+	raw := fs.ZVString("raw", "", "raw")
+	port := fs.NDInt("port", 0, "port")
+	level := fs.ZVString("log.level", "", "log level")
+	empty := fs.NDString("empty", "", "empty")
+	missing := fs.NDString("missing", "", "not in the file")
+
+	if err := fs.Parse([]string{"-cli-wins=from cli"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.ParseEnvFile(p); err != nil {
+		t.Fatal(err)
+	}
+	if **name != "from file" || **cli != "from cli" {
+		t.Errorf("bad precedence: %q %q", **name, **cli)
+	}
+	if **greeting != "hello \"world\"\nbye" {
+		t.Errorf("bad greeting: %q", **greeting)
+	}
+	if *raw != `no \n escapes # here` {
+		t.Errorf("bad raw: %q", *raw)
+	}
+	if **port != 8080 || *level != "debug" {
+		t.Errorf("bad port / level: %d %q", **port, *level)
+	}
+	if *empty == nil || **empty != "" {
+		t.Error("empty value should still set the flag")
+	}
+	if *missing != nil {
+		t.Error("missing should stay nil")
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.env")
+	os.WriteFile(bad, []byte("MYAPP_PORT=x\n"), 0o600)
+	fs.Reset()
+	if err := fs.ParseEnvFile(bad); err == nil || !strings.Contains(err.Error(), `invalid value "x" for MYAPP_PORT (flag -port)`) {
+		t.Errorf("bad error: %v", err)
+	}
+	os.WriteFile(bad, []byte("# ok\nNOT A PAIR\n"), 0o600)
+	if err := fs.ParseEnvFile(bad); err == nil || !strings.Contains(err.Error(), "bad.env:2: expected KEY=value") {
+		t.Errorf("bad error: %v", err)
+	}
+	if err := fs.ParseEnvFile(filepath.Join(t.TempDir(), "nope.env")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestParseEnvFileChecksOnce(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(p, []byte("PORT=8080\nOLD=x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	newSet := func() (*NDFlagSet, *strings.Builder, map[string]int) {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		out := &strings.Builder{}
+		fs.SetOutput(out)
+		runs := map[string]int{}
+		for _, name := range []string{"host", "port", "old", "legacy"} {
+			name := name
+			fs.NDString(name, "", name)
+			fs.AddValidator(name, func(interface{}) error {
+				runs[name]++
+				return nil
+			})
+		}
+		fs.Deprecate("legacy", "drop it")
+		fs.Deprecate("old", "drop it too")
+		return fs, out, runs
+	}
+
+	fs, out, runs := newSet()
+	if err := fs.Parse([]string{"-host=h", "-legacy=l"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.ParseEnvFile(p); err != nil {
+		t.Fatal(err)
+	}
+	if runs["host"] != 1 || runs["legacy"] != 1 || runs["port"] != 1 || runs["old"] != 1 {
+		t.Errorf("each validator should run once, got %v", runs)
+	}
+	want := "flag -legacy is deprecated: drop it\nflag -old is deprecated: drop it too\n"
+	if out.String() != want {
+		t.Errorf("each warning should print once, got %q", out.String())
+	}
+
+	fs, out, runs = newSet()
+	if err := fs.ParseWithEnvFile([]string{"-host=h", "-legacy=l"}, p); err != nil {
+		t.Fatal(err)
+	}
+	if runs["host"] != 1 || runs["legacy"] != 1 || runs["port"] != 1 || runs["old"] != 1 {
+		t.Errorf("each validator should run once, got %v", runs)
+	}
+	if out.String() != want {
+		t.Errorf("each warning should print once, got %q", out.String())
+	}
+}
+
+func TestEnvPerFlag(t *testing.T) {
+	setenv(t, "LEGACY_HOST", "env-host")
+	setenv(t, "LEGACY_PORT", "8080")
+	setenv(t, "LEGACY_BAD", "x")
+	defer func() {
+		for _, k := range []string{"LEGACY_HOST", "LEGACY_PORT", "LEGACY_BAD"} {
+			os.Unsetenv(k)
+		}
+	}()
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	cli := fs.NDStringEnv("cli", "LEGACY_HOST", "", "string value")
+	host := fs.NDStringEnv("host", "LEGACY_HOST", "", "string value")
+	port := fs.ZVIntEnv("port", "LEGACY_PORT", 0, "int value")
+	neither := fs.NDStringEnv("neither", "LEGACY_NOPE", "", "string value")
+
+	if err := fs.Parse([]string{"-cli=from cli"}); err != nil {
+		t.Fatal(err)
+	}
+	if *cli == nil || **cli != "from cli" {
+		t.Errorf("cli should win, got %v", *cli)
+	}
+	if *host == nil || **host != "env-host" {
+		t.Errorf("host should come from env, got %v", *host)
+	}
+	if *port != 8080 || !fs.IsSet("port") {
+		t.Errorf("port should come from env, got %d", *port)
+	}
+	if *neither != nil || fs.IsSet("neither") {
+		t.Errorf("neither should be unset, got %q", **neither)
+	}
+	if c := fs.Clone("clone"); c.Parse(nil) != nil || !c.IsSet("host") {
+		t.Errorf("clone should keep the env binding")
+	}
+
+	fs = NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDIntEnv("bad", "LEGACY_BAD", 0, "int value")
+	if err := fs.Parse(nil); err == nil || !strings.Contains(err.Error(), "LEGACY_BAD") {
+		t.Errorf("expected an error naming the variable, got %v", err)
+	}
+}
+
+func TestEnvPerFlagDeprecated(t *testing.T) {
+	setenv(t, "NDTEST_DEPRECATED_LEGACY", "c")
+	defer os.Unsetenv("NDTEST_DEPRECATED_LEGACY")
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	out := &strings.Builder{}
+	fs.SetOutput(out)
+	fs.NDString("legacy", "", "legacy name")
+	fs.Deprecate("legacy", "drop it")
+	fs.BindEnv("legacy", "NDTEST_DEPRECATED_LEGACY")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "flag -legacy is deprecated: drop it\n"; out.String() != want {
+		t.Errorf("setting from a bound env var should warn, got %q", out.String())
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	setenv(t, "NDFLAG_TEST_DIR", "/srv")
+	setenv(t, "NDFLAG_TEST_N", "5")
+	os.Unsetenv("NDFLAG_TEST_UNSET")
+	defer os.Unsetenv("NDFLAG_TEST_DIR")
+	defer os.Unsetenv("NDFLAG_TEST_N")
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	dir := fs.NDString("dir", "", "dir")
+	path := fs.ZVPath("path", "", PathOptions{}, "path")
+	n := fs.ZVInt("n", 0, "an int")
+
+	// off by default
+	if err := fs.Parse([]string{"-dir=$NDFLAG_TEST_DIR/data"}); err != nil {
+		t.Fatal(err)
+	}
+	if **dir != "$NDFLAG_TEST_DIR/data" {
+		t.Errorf("expected no expansion, got %q", **dir)
+	}
+
+	fs.Reset()
+	fs.SetExpandEnv(true)
+	fs.AddTransform("dir", strings.ToUpper)
+	if err := fs.Parse([]string{"-dir=$NDFLAG_TEST_DIR/data", "-path=${NDFLAG_TEST_UNSET}/x"}); err != nil {
+		t.Fatal(err)
+	}
+	if **dir != "/SRV/DATA" {
+		t.Errorf("expected expansion before the transform, got %q", **dir)
+	}
+	if *path != "/x" {
+		t.Errorf("unset variables should expand to nothing, got %q", *path)
+	}
+
+	if err := fs.Parse([]string{"-n=$NDFLAG_TEST_N"}); err == nil {
+		t.Errorf("only string flags should expand, got %d", *n)
+	}
+}
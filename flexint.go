@@ -0,0 +1,41 @@
+package nodefflag
+
+import (
+	"flag"
+	"strconv"
+)
+
+// SetFlexibleIntParsing - with on, the integer flags, slices included,
+// take 0x, 0o and 0b prefixed values and _ between digits, as in Go
+// integer literals, e.g. 0xFF or 1_000_000.  As in Go, a leading 0 alone
+// means octal, so 010 is 8 and 0755 is 493, and _ may follow a prefix or
+// that 0, so 0_1 is 1.  Off by default, for compatibility.
+func (ndf *NDFlagSet) SetFlexibleIntParsing(on bool) {
+	ndf.flexInts = on
+}
+
+// intKinder - implemented by the generic Value types, see intKind.
+type intKinder interface {
+	intKind() (bits int, signed, ok bool)
+}
+
+// flexibleInt - val in plain decimal, if v is an integer flag and val an
+// integer literal in range, otherwise val as is, for v to reject.
+func flexibleInt(v flag.Value, val string) string {
+	k, ok := v.(intKinder)
+	if !ok {
+		return val
+	}
+	bits, signed, ok := k.intKind()
+	if !ok {
+		return val
+	}
+	if signed {
+		if i, err := strconv.ParseInt(val, 0, bits); err == nil {
+			return strconv.FormatInt(i, 10)
+		}
+	} else if ui, err := strconv.ParseUint(val, 0, bits); err == nil {
+		return strconv.FormatUint(ui, 10)
+	}
+	return val
+}
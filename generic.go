@@ -0,0 +1,745 @@
+package nodefflag
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image/color"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The Value implementations use pointer receivers, and are only ever
+// registered as pointers, apart from funcf and helpf, which hold no state
+// of their own and are registered as is.
+var (
+	_ flag.Getter = (*ndv[uint])(nil)
+	_ flag.Getter = (*zvv[uint])(nil)
+	_ flag.Getter = (*ndsv[string])(nil)
+	_ flag.Getter = (*zvsv[string])(nil)
+	_ flag.Getter = (*ndenumf)(nil)
+	_ flag.Getter = (*zvenumf)(nil)
+	_ flag.Getter = (*ndcountf)(nil)
+	_ flag.Getter = (*zvcountf)(nil)
+	_ flag.Getter = (*ndsmf)(nil)
+	_ flag.Getter = (*zvsmf)(nil)
+	_ flag.Getter = (*negbf)(nil)
+	_ flag.Getter = funcf(nil)
+	_ flag.Value  = helpf{}
+	_ flag.Getter = (*trackedValue)(nil)
+)
+
+// intKind - the bit size and signedness of T if it's an integer type,
+// ok is false otherwise.
+func intKind[T any]() (bits int, signed, ok bool) {
+	var zero T
+	switch any(zero).(type) {
+	case int:
+		return strconv.IntSize, true, true
+	case int8:
+		return 8, true, true
+	case int16:
+		return 16, true, true
+	case int32:
+		return 32, true, true
+	case int64:
+		return 64, true, true
+	case uint:
+		return strconv.IntSize, false, true
+	case uint8:
+		return 8, false, true
+	case uint16:
+		return 16, false, true
+	case uint32:
+		return 32, false, true
+	case uint64:
+		return 64, false, true
+	}
+	return 0, false, false
+}
+
+// ndv - the Value implementation behind every "no default" scalar flag.
+// parse turns the argument into a T, which is stored in a fresh
+// allocation so the double pointer only goes non-nil once set.  def is
+// only set by the ND*Default methods, see MaterializeDefaults, lazy only
+// by NDStringFunc, see ResolveDefaults, and stdin marks the flags
+// SetStdinSentinel applies to.
+type ndv[T any] struct {
+	v       **T
+	parse   func(string) (T, error)
+	example string
+	isBool  bool
+	quoted  bool
+	stdin   bool
+	def     *T
+	lazy    func() T
+}
+
+func (n *ndv[T]) String() string {
+	return n.example
+}
+
+func (n *ndv[T]) Set(val string) error {
+	p, err := n.parse(val)
+	if err != nil {
+		return err
+	}
+	*n.v = &p
+	return nil
+}
+
+func (n *ndv[T]) Get() interface{} {
+	return *n.v
+}
+
+func (n *ndv[T]) intKind() (int, bool, bool) {
+	return intKind[T]()
+}
+
+func (n *ndv[T]) reset() {
+	*n.v = nil
+}
+
+func (n *ndv[T]) materialize() {
+	if *n.v != nil {
+		return
+	}
+	if n.lazy != nil {
+		n.resolve()
+	} else if n.def != nil {
+		d := *n.def
+		*n.v = &d
+	} else if p, err := n.parse(n.example); err == nil {
+		*n.v = &p
+	}
+}
+
+func (n *ndv[T]) resolve() {
+	if *n.v == nil && n.lazy != nil {
+		d := n.lazy()
+		*n.v = &d
+	}
+}
+
+func (n *ndv[T]) hasDefault() bool {
+	return n.def != nil
+}
+
+func (n *ndv[T]) parsedDefault() (interface{}, bool) {
+	if n.lazy != nil {
+		// only resolve calls it
+		return nil, false
+	}
+	if n.def != nil {
+		return *n.def, true
+	}
+	p, err := n.parse(n.example)
+	return p, err == nil
+}
+
+func (n *ndv[T]) IsBoolFlag() bool {
+	return n.isBool
+}
+
+func (n *ndv[T]) quoteExample() bool {
+	return n.quoted
+}
+
+func (n *ndv[T]) takesStdin() bool {
+	return n.stdin
+}
+
+// zvv - the zero value counterpart of ndv.  def is only set by the
+// ZV*Default methods, otherwise the default is the zero value.
+type zvv[T any] struct {
+	v       *T
+	parse   func(string) (T, error)
+	example string
+	isBool  bool
+	quoted  bool
+	stdin   bool
+	def     *T
+}
+
+func (z *zvv[T]) String() string {
+	return z.example
+}
+
+func (z *zvv[T]) Set(val string) error {
+	p, err := z.parse(val)
+	if err != nil {
+		return err
+	}
+	*z.v = p
+	return nil
+}
+
+func (z *zvv[T]) Get() interface{} {
+	return *z.v
+}
+
+func (z *zvv[T]) intKind() (int, bool, bool) {
+	return intKind[T]()
+}
+
+func (z *zvv[T]) reset() {
+	if z.def != nil {
+		*z.v = *z.def
+		return
+	}
+	var zero T
+	*z.v = zero
+}
+
+func (z *zvv[T]) hasDefault() bool {
+	return z.def != nil
+}
+
+func (z *zvv[T]) parsedDefault() (interface{}, bool) {
+	if z.def != nil {
+		return *z.def, true
+	}
+	p, err := z.parse(z.example)
+	return p, err == nil
+}
+
+func (z *zvv[T]) IsBoolFlag() bool {
+	return z.isBool
+}
+
+func (z *zvv[T]) quoteExample() bool {
+	return z.quoted
+}
+
+func (z *zvv[T]) takesStdin() bool {
+	return z.stdin
+}
+
+func (z *zvv[T]) zeroValue() {}
+
+// startValue - what the target holds, as the usage shows it: the example
+// if that's what it holds, otherwise see formatStart.
+func (z *zvv[T]) startValue() (string, bool) {
+	if p, err := z.parse(z.example); err == nil && reflect.DeepEqual(p, *z.v) {
+		return z.example, true
+	}
+	return formatStart(*z.v)
+}
+
+// formatStart - v for the usage, its String if it has one.  Zero values
+// of anything but bools, numbers and strings, like a nil *regexp.Regexp
+// or the zero time.Time, have nothing worth showing, so ok is false.
+func formatStart[T any](v T) (string, bool) {
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+	default:
+		if rv.IsZero() {
+			return "", false
+		}
+	}
+	if s, ok := any(v).(fmt.Stringer); ok {
+		return s.String(), true
+	}
+	return fmt.Sprint(v), true
+}
+
+// ndsv - repeatable "no default" flag, each occurrence is parsed and
+// appended.
+type ndsv[T any] struct {
+	v     **[]T
+	parse func(string) (T, error)
+}
+
+func (n *ndsv[T]) String() string {
+	return ""
+}
+
+func (n *ndsv[T]) Set(val string) error {
+	p, err := n.parse(val)
+	if err != nil {
+		return err
+	}
+	if *n.v == nil {
+		*n.v = &[]T{}
+	}
+	**n.v = append(**n.v, p)
+	return nil
+}
+
+func (n *ndsv[T]) Get() interface{} {
+	return *n.v
+}
+
+func (n *ndsv[T]) intKind() (int, bool, bool) {
+	return intKind[T]()
+}
+
+func (n *ndsv[T]) repeatable() {}
+
+func (n *ndsv[T]) reset() {
+	*n.v = nil
+}
+
+// zvsv - the zero value counterpart of ndsv.
+type zvsv[T any] struct {
+	v     *[]T
+	parse func(string) (T, error)
+}
+
+func (z *zvsv[T]) String() string {
+	return ""
+}
+
+func (z *zvsv[T]) Set(val string) error {
+	p, err := z.parse(val)
+	if err != nil {
+		return err
+	}
+	*z.v = append(*z.v, p)
+	return nil
+}
+
+func (z *zvsv[T]) Get() interface{} {
+	return *z.v
+}
+
+func (z *zvsv[T]) intKind() (int, bool, bool) {
+	return intKind[T]()
+}
+
+func (z *zvsv[T]) repeatable() {}
+
+func (z *zvsv[T]) reset() {
+	*z.v = []T{}
+}
+
+func (z *zvsv[T]) zeroValue() {}
+
+// NDValue - generic version of the ND methods, for types there isn't a
+// dedicated method for.  parse converts the argument, and the example is
+// rendered with fmt.Sprint.  The double pointer will reference nil if the
+// flag was not set.
+func NDValue[T any](ndf *NDFlagSet, name string, parse func(string) (T, error), example T, usage string) **T {
+	var v *T
+	NDValueVar(ndf, &v, name, parse, example, usage)
+	return &v
+}
+
+// NDValueVar - BYO pp version of NDValue
+func NDValueVar[T any](ndf *NDFlagSet, v **T, name string, parse func(string) (T, error), example T, usage string) {
+	n := &ndv[T]{v: v, parse: parse, example: fmt.Sprint(example)}
+	ndf.Var(n, name, usage)
+}
+
+// ZVValue - generic version of the ZV methods, see NDValue.
+func ZVValue[T any](ndf *NDFlagSet, name string, parse func(string) (T, error), example T, usage string) *T {
+	var v T
+	ZVValueVar(ndf, &v, name, parse, example, usage)
+	return &v
+}
+
+// ZVValueVar - BYO pointer version of ZVValue
+func ZVValueVar[T any](ndf *NDFlagSet, v *T, name string, parse func(string) (T, error), example T, usage string) {
+	z := &zvv[T]{v: v, parse: parse, example: fmt.Sprint(example)}
+	ndf.Var(z, name, usage)
+}
+
+// NDJSON - flag whose argument is JSON, unmarshaled into a T, e.g.
+// -config='{"workers":4}' into a struct.  The example is shown marshaled.
+// The double pointer will reference nil if the flag was not set.
+func NDJSON[T any](ndf *NDFlagSet, name string, example T, usage string) **T {
+	var v *T
+	NDJSONVar(ndf, &v, name, example, usage)
+	return &v
+}
+
+// NDJSONVar - BYO pp version of NDJSON
+func NDJSONVar[T any](ndf *NDFlagSet, v **T, name string, example T, usage string) {
+	n := &ndv[T]{v: v, parse: parseJSON[T], example: jsonExample(example)}
+	ndf.Var(n, name, usage)
+}
+
+// ZVJSON - zero value version of NDJSON.
+func ZVJSON[T any](ndf *NDFlagSet, name string, example T, usage string) *T {
+	var v T
+	ZVJSONVar(ndf, &v, name, example, usage)
+	return &v
+}
+
+// ZVJSONVar - BYO pointer version of ZVJSON
+func ZVJSONVar[T any](ndf *NDFlagSet, v *T, name string, example T, usage string) {
+	z := &zvv[T]{v: v, parse: parseJSON[T], example: jsonExample(example)}
+	ndf.Var(z, name, usage)
+}
+
+// The parse funcs used by the typed methods, where the strconv / time /
+// net ones don't already have the right signature.
+
+func parseString(val string) (string, error) {
+	return val, nil
+}
+
+// parseBoolExtended - strconv.ParseBool, plus the yes/no style keywords.
+func parseBoolExtended(val string) (bool, error) {
+	switch strings.ToLower(val) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q", val)
+	}
+	return b, nil
+}
+
+// parseRune - a single character, a Go escape or U+XXXX.
+func parseRune(val string) (rune, error) {
+	switch {
+	case strings.HasPrefix(val, `\`) && len(val) > 1:
+		r, _, tail, err := strconv.UnquoteChar(val, '\'')
+		if err == nil && tail == "" {
+			return r, nil
+		}
+	case strings.HasPrefix(val, "U+") && len(val) > 2:
+		n, err := strconv.ParseUint(val[2:], 16, 32)
+		if err == nil && utf8.ValidRune(rune(n)) {
+			return rune(n), nil
+		}
+	default:
+		r, size := utf8.DecodeRuneInString(val)
+		if r != utf8.RuneError && size == len(val) {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid character %q, expected exactly one", val)
+}
+
+// runeExample - the example as parseRune takes it, escaped if it isn't
+// printable, empty for 0.
+func runeExample(example rune) string {
+	if example == 0 {
+		return ""
+	}
+	q := strconv.QuoteRune(example)
+	return q[1 : len(q)-1]
+}
+
+func parseSigned[T int8 | int16 | int32 | int64](bits int) func(string) (T, error) {
+	return func(val string) (T, error) {
+		i, err := strconv.ParseInt(val, 10, bits)
+		return T(i), err
+	}
+}
+
+func parseUnsigned[T uint | uint8 | uint16 | uint32 | uint64](bits int) func(string) (T, error) {
+	return func(val string) (T, error) {
+		ui, err := strconv.ParseUint(val, 10, bits)
+		return T(ui), err
+	}
+}
+
+func parseFloat[T float32 | float64](bits int) func(string) (T, error) {
+	return func(val string) (T, error) {
+		f, err := strconv.ParseFloat(val, bits)
+		return T(f), err
+	}
+}
+
+func parseComplex(val string) (complex128, error) {
+	return strconv.ParseComplex(val, 128)
+}
+
+// parseFiniteFloat - strconv.ParseFloat, without NaN or +-Inf.
+func parseFiniteFloat(val string) (float64, error) {
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", val)
+	}
+	return f, nil
+}
+
+// parseRGBA - #rgb, #rgba, #rrggbb or #rrggbbaa, the # optional, as
+// colors are written in CSS.  Alpha defaults to opaque.
+func parseRGBA(val string) (color.RGBA, error) {
+	s := strings.TrimPrefix(val, "#")
+	if len(s) == 3 || len(s) == 4 {
+		var b strings.Builder
+		for _, r := range s {
+			b.WriteRune(r)
+			b.WriteRune(r)
+		}
+		s = b.String()
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 4 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, want #rrggbb or #rrggbbaa", val)
+	}
+	return color.RGBA{R: b[0], G: b[1], B: b[2], A: b[3]}, nil
+}
+
+func parseTime(layout string) func(string) (time.Time, error) {
+	return func(val string) (time.Time, error) {
+		return time.Parse(layout, val)
+	}
+}
+
+// parseUnixTime - epoch seconds, whole or fractional.
+func parseUnixTime(val string) (time.Time, error) {
+	if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return time.Unix(n, 0), nil
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, fmt.Errorf("invalid unix time %q", val)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))), nil
+}
+
+// unixExample - the example as epoch seconds, empty for the zero time.
+func unixExample(example time.Time) string {
+	if example.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(example.Unix(), 10)
+}
+
+func parseIP(val string) (net.IP, error) {
+	ip := net.ParseIP(val)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", val)
+	}
+	return ip, nil
+}
+
+// parseIPNet - net.ParseCIDR, optionally keeping the host part of the
+// address rather than the network.
+func parseIPNet(keepHost bool) func(string) (net.IPNet, error) {
+	return func(val string) (net.IPNet, error) {
+		ip, pn, err := net.ParseCIDR(val)
+		if err != nil {
+			return net.IPNet{}, err
+		}
+		if keepHost {
+			pn.IP = ip
+		}
+		return *pn, nil
+	}
+}
+
+// parseURL - url.Parse, optionally requiring a scheme.
+func parseURL(abs bool) func(string) (url.URL, error) {
+	return func(val string) (url.URL, error) {
+		pu, err := url.Parse(val)
+		if err != nil {
+			return url.URL{}, err
+		}
+		if abs && pu.Scheme == "" {
+			return url.URL{}, fmt.Errorf("url %q has no scheme", val)
+		}
+		return *pu, nil
+	}
+}
+
+// parseBigInt - returns the value rather than a pointer so it fits ndv
+// and zvv, which is fine as the copy is the only reference to it.
+func parseBigInt(val string) (big.Int, error) {
+	b, ok := new(big.Int).SetString(val, 0)
+	if !ok {
+		return big.Int{}, fmt.Errorf("invalid integer %q", val)
+	}
+	return *b, nil
+}
+
+// bigIntExample - String() of the example, tolerating nil.
+func bigIntExample(example *big.Int) string {
+	if example == nil {
+		return ""
+	}
+	return example.String()
+}
+
+func parseRegexp(val string) (regexp.Regexp, error) {
+	re, err := regexp.Compile(val)
+	if err != nil {
+		return regexp.Regexp{}, err
+	}
+	return *re, nil
+}
+
+// parseJSON - unmarshals into a fresh T, so nothing is left over from
+// an earlier occurrence of the flag.
+func parseJSON[T any](val string) (T, error) {
+	var v T
+	if err := json.Unmarshal([]byte(val), &v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// jsonExample - the example marshaled, empty if it can't be.
+func jsonExample(example interface{}) string {
+	b, err := json.Marshal(example)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// byteUnits - multipliers for parseBytes, by lowercased suffix.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"kib": 1 << 10,
+	"m":   1e6,
+	"mb":  1e6,
+	"mib": 1 << 20,
+	"g":   1e9,
+	"gb":  1e9,
+	"gib": 1 << 30,
+	"t":   1e12,
+	"tb":  1e12,
+	"tib": 1 << 40,
+	"p":   1e15,
+	"pb":  1e15,
+	"pib": 1 << 50,
+}
+
+// parseBytes - parses a human readable size like 512, 10KB or 1.5GiB
+// into a byte count.  The suffix is case insensitive, the KB style ones
+// are decimal and the KiB style ones binary.
+func parseBytes(val string) (int64, error) {
+	s := strings.TrimSpace(val)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	mult, ok := byteUnits[unit]
+	if !ok || num == "" {
+		return 0, fmt.Errorf("invalid byte size %q", val)
+	}
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil && mult == 1 {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", val)
+	}
+	f *= mult
+	if f >= 1<<63 {
+		return 0, fmt.Errorf("byte size %q out of range", val)
+	}
+	return int64(f), nil
+}
+
+// parseUnit - parses a number with one of the suffixes in units, like
+// 500ms, into base units by multiplying with the suffix's factor.  A
+// bare number is taken to be in base.
+func parseUnit(units map[string]float64, base string) func(string) (float64, error) {
+	return func(val string) (float64, error) {
+		s := strings.TrimSpace(val)
+		sign := len(s) - len(strings.TrimLeft(s, "+-"))
+		i := strings.IndexFunc(s[sign:], func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if i < 0 {
+			i = len(s)
+		} else {
+			i += sign
+		}
+		num, unit := s[:i], strings.TrimSpace(s[i:])
+		if unit == "" {
+			unit = base
+		}
+		mult, ok := units[unit]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q in %q, must be one of: %s", unit, val, strings.Join(unitNames(units), ", "))
+		}
+		f, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number in %q", val)
+		}
+		return f * mult, nil
+	}
+}
+
+// unitNames - the sorted suffixes of units.
+func unitNames(units map[string]float64) []string {
+	names := make([]string, 0, len(units))
+	for u := range units {
+		names = append(names, u)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unitExample - the example with the base suffix, checking base is one
+// of units while at it.
+func unitExample(units map[string]float64, base string, example float64) string {
+	if _, ok := units[base]; !ok {
+		panic(fmt.Sprintf("unit flag base %q is not one of its units", base))
+	}
+	return strconv.FormatFloat(example, 'g', -1, 64) + base
+}
+
+// percentExample - the example as a percentage, 0.25 is shown as 25%.
+func percentExample(example float64) string {
+	return strconv.FormatFloat(example*100, 'g', -1, 64) + "%"
+}
+
+// colorExample - the example as #rrggbb, with the alpha only if it's not
+// opaque.
+func colorExample(example color.RGBA) string {
+	s := fmt.Sprintf("#%02x%02x%02x", example.R, example.G, example.B)
+	if example.A != 0xff {
+		s += fmt.Sprintf("%02x", example.A)
+	}
+	return s
+}
+
+// netipExample - String() of the example, or empty for the zero value,
+// which would otherwise render as "invalid IP".
+func netipExample[T interface {
+	IsValid() bool
+	String() string
+}](example T) string {
+	if !example.IsValid() {
+		return ""
+	}
+	return example.String()
+}
+
+// urlExample - String() of the example, tolerating nil.
+func urlExample(example *url.URL) string {
+	if example == nil {
+		return ""
+	}
+	return example.String()
+}
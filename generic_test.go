@@ -0,0 +1,157 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+type shade int
+
+const (
+	red shade = iota
+	green
+)
+
+func (c shade) String() string {
+	return [...]string{"red", "green"}[c]
+}
+
+func parseColor(val string) (shade, error) {
+	switch val {
+	case "red":
+		return red, nil
+	case "green":
+		return green, nil
+	}
+	return 0, fmt.Errorf("unknown color %q", val)
+}
+
+func TestGenericValue(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	addr := NDValue(fs, "addr", netip.ParseAddr, netip.MustParseAddr("127.0.0.1"), "address")
+	unset := NDValue(fs, "unset", netip.ParseAddr, netip.Addr{}, "address")
+	zc := ZVValue(fs, "color", parseColor, green, "color")
+
+	if d := fs.Lookup("addr").DefValue; d != "127.0.0.1" {
+		t.Errorf("bad example for addr: %q", d)
+	}
+	if d := fs.Lookup("color").DefValue; d != "green" {
+		t.Errorf("bad example for color: %q", d)
+	}
+	if *zc != red {
+		t.Errorf("unset zv color should be the zero value, got %s", *zc)
+	}
+
+	if err := fs.Parse([]string{"-addr=::1", "-color=green"}); err != nil {
+		t.Fatal(err)
+	}
+	if *unset != nil {
+		t.Error("unset should reference nil")
+	}
+	if *addr == nil || **addr != netip.IPv6Loopback() {
+		t.Errorf("bad addr: %v", *addr)
+	}
+	if *zc != green || !fs.IsSet("color") {
+		t.Errorf("bad color: %s", *zc)
+	}
+	if err := fs.Set("color", "blue"); err == nil {
+		t.Error("expected unknown color to fail")
+	}
+
+	var c *shade
+	NDValueVar(fs, &c, "nd_color", parseColor, red, "color")
+	if err := fs.Set("nd_color", "green"); err != nil {
+		t.Fatal(err)
+	}
+	if c == nil || *c != green {
+		t.Errorf("bad nd_color: %v", c)
+	}
+	if v, ok := typedValue[shade](fs, "nd_color"); !ok || v != green {
+		t.Errorf("bad typed nd_color: %v %v", v, ok)
+	}
+}
+
+func TestGetReturnsTarget(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	var (
+		uiv *uint
+		dv  *time.Duration
+	)
+	fs.NDUintVar(&uiv, "uint", 5, "uint value")
+	fs.NDDurationVar(&dv, "duration", time.Second, "duration value")
+	zdv := fs.ZVDuration("zv_duration", time.Second, "duration value")
+
+	get := func(name string) interface{} {
+		return fs.Lookup(name).Value.(flag.Getter).Get()
+	}
+
+	if get("uint") != (*uint)(nil) || get("duration") != (*time.Duration)(nil) {
+		t.Error("unset Get should return a nil pointer")
+	}
+
+	_ = fs.Set("uint", "80")
+	_ = fs.Set("duration", "30s")
+	_ = fs.Set("zv_duration", "1m")
+
+	if p := get("uint"); p != uiv || *uiv != 80 {
+		t.Errorf("bad uint: %#v", p)
+	}
+	if p := get("duration"); p != dv || *dv != 30*time.Second {
+		t.Errorf("bad duration: %#v", p)
+	}
+	if v := get("zv_duration"); v != *zdv || *zdv != time.Minute {
+		t.Errorf("bad zv_duration: %#v", v)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type config struct {
+		Workers int    `json:"workers"`
+		Name    string `json:"name"`
+	}
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := NDJSON(fs, "config", config{Workers: 2}, "config value")
+	zv := ZVJSON(fs, "ports", []int(nil), "ports value")
+
+	if *nd != nil || *zv != nil {
+		t.Error("unset json flags should be nil")
+	}
+	if d := fs.Lookup("config").DefValue; d != `{"workers":2,"name":""}` {
+		t.Errorf("bad example for config: %q", d)
+	}
+
+	err := fs.Parse([]string{`-config={"workers":4,"name":"x"}`, "-ports", "[80, 443]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if **nd != (config{Workers: 4, Name: "x"}) {
+		t.Errorf("bad config: %+v", **nd)
+	}
+	if len(*zv) != 2 || (*zv)[0] != 80 || (*zv)[1] != 443 {
+		t.Errorf("bad ports: %v", *zv)
+	}
+
+	// each occurrence starts from scratch
+	if err := fs.Parse([]string{`-config={"name":"y"}`}); err != nil {
+		t.Fatal(err)
+	}
+	if **nd != (config{Name: "y"}) {
+		t.Errorf("bad config: %+v", **nd)
+	}
+
+	for _, bad := range [][]string{
+		{`-config={"workers":`},
+		{`-config={"workers":"four"}`},
+		{"-ports=80"},
+	} {
+		if err := fs.Parse(bad); err == nil || !strings.Contains(err.Error(), "invalid value") {
+			t.Errorf("expected %q to fail, got %v", bad, err)
+		}
+	}
+}
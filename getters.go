@@ -0,0 +1,309 @@
+package nodefflag
+
+import (
+	"flag"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// getSet - returns the Get() result of the named flag, if it's known
+// and was set.
+func (ndf *NDFlagSet) getSet(name string) (interface{}, bool) {
+	fl := ndf.Lookup(name)
+	if fl == nil || !ndf.IsSet(name) {
+		return nil, false
+	}
+	g, ok := fl.Value.(flag.Getter)
+	if !ok {
+		return nil, false
+	}
+	return g.Get(), true
+}
+
+// typedValue - resolves the Get() result of the named flag to a T, which
+// the ND variants return as a *T and the ZV variants as a T.
+func typedValue[T any](ndf *NDFlagSet, name string) (T, bool) {
+	v, _ := ndf.getSet(name)
+	switch v := v.(type) {
+	case *T:
+		if v != nil {
+			return *v, true
+		}
+	case T:
+		return v, true
+	}
+	var zero T
+	return zero, false
+}
+
+// StringValue - returns the value of the named string flag and whether
+// it was set, so you don't have to hang on to / dereference the double
+// pointer.  Works with both the ND and ZV variants, returns ("", false)
+// for unknown or unset flags.
+func (ndf *NDFlagSet) StringValue(name string) (string, bool) {
+	return typedValue[string](ndf, name)
+}
+
+// BoolValue - bool version of StringValue
+func (ndf *NDFlagSet) BoolValue(name string) (bool, bool) {
+	return typedValue[bool](ndf, name)
+}
+
+// IntValue - int version of StringValue
+func (ndf *NDFlagSet) IntValue(name string) (int, bool) {
+	return typedValue[int](ndf, name)
+}
+
+// Int8Value - int8 version of StringValue
+func (ndf *NDFlagSet) Int8Value(name string) (int8, bool) {
+	return typedValue[int8](ndf, name)
+}
+
+// Int16Value - int16 version of StringValue
+func (ndf *NDFlagSet) Int16Value(name string) (int16, bool) {
+	return typedValue[int16](ndf, name)
+}
+
+// Int32Value - int32 version of StringValue
+func (ndf *NDFlagSet) Int32Value(name string) (int32, bool) {
+	return typedValue[int32](ndf, name)
+}
+
+// Int64Value - int64 version of StringValue
+func (ndf *NDFlagSet) Int64Value(name string) (int64, bool) {
+	return typedValue[int64](ndf, name)
+}
+
+// UintValue - uint version of StringValue
+func (ndf *NDFlagSet) UintValue(name string) (uint, bool) {
+	return typedValue[uint](ndf, name)
+}
+
+// Uint8Value - uint8 version of StringValue
+func (ndf *NDFlagSet) Uint8Value(name string) (uint8, bool) {
+	return typedValue[uint8](ndf, name)
+}
+
+// Uint16Value - uint16 version of StringValue
+func (ndf *NDFlagSet) Uint16Value(name string) (uint16, bool) {
+	return typedValue[uint16](ndf, name)
+}
+
+// Uint32Value - uint32 version of StringValue
+func (ndf *NDFlagSet) Uint32Value(name string) (uint32, bool) {
+	return typedValue[uint32](ndf, name)
+}
+
+// Uint64Value - uint64 version of StringValue
+func (ndf *NDFlagSet) Uint64Value(name string) (uint64, bool) {
+	return typedValue[uint64](ndf, name)
+}
+
+// Float32Value - float32 version of StringValue
+func (ndf *NDFlagSet) Float32Value(name string) (float32, bool) {
+	return typedValue[float32](ndf, name)
+}
+
+// Float64Value - float64 version of StringValue
+func (ndf *NDFlagSet) Float64Value(name string) (float64, bool) {
+	return typedValue[float64](ndf, name)
+}
+
+// DurationValue - time.Duration version of StringValue
+func (ndf *NDFlagSet) DurationValue(name string) (time.Duration, bool) {
+	return typedValue[time.Duration](ndf, name)
+}
+
+// TimeValue - time.Time version of StringValue
+func (ndf *NDFlagSet) TimeValue(name string) (time.Time, bool) {
+	return typedValue[time.Time](ndf, name)
+}
+
+// IPValue - net.IP version of StringValue
+func (ndf *NDFlagSet) IPValue(name string) (net.IP, bool) {
+	return typedValue[net.IP](ndf, name)
+}
+
+// IPNetValue - net.IPNet version of StringValue, returns (nil, false) for
+// unknown or unset flags.
+func (ndf *NDFlagSet) IPNetValue(name string) (*net.IPNet, bool) {
+	if v, ok := typedValue[net.IPNet](ndf, name); ok {
+		return &v, true
+	}
+	return nil, false
+}
+
+// URLValue - url.URL version of StringValue, returns (nil, false) for
+// unknown or unset flags.
+func (ndf *NDFlagSet) URLValue(name string) (*url.URL, bool) {
+	if v, ok := typedValue[url.URL](ndf, name); ok {
+		return &v, true
+	}
+	return nil, false
+}
+
+// StringSliceValue - returns the collected values of the named slice
+// flag and whether it was set, works for the repeatable and the CSV
+// variants.  A flag that was set can still hold an empty slice, e.g.
+// -tags= for a CSV flag; returns (nil, false) for unknown or unset flags.
+func (ndf *NDFlagSet) StringSliceValue(name string) ([]string, bool) {
+	return typedValue[[]string](ndf, name)
+}
+
+// IntSliceValue - []int version of StringSliceValue
+func (ndf *NDFlagSet) IntSliceValue(name string) ([]int, bool) {
+	return typedValue[[]int](ndf, name)
+}
+
+// Float64SliceValue - []float64 version of StringSliceValue
+func (ndf *NDFlagSet) Float64SliceValue(name string) ([]float64, bool) {
+	return typedValue[[]float64](ndf, name)
+}
+
+// DurationSliceValue - []time.Duration version of StringSliceValue
+func (ndf *NDFlagSet) DurationSliceValue(name string) ([]time.Duration, bool) {
+	return typedValue[[]time.Duration](ndf, name)
+}
+
+// SetValues - returns the Get() result of every flag that was set, keyed
+// by flag name, e.g. for logging the effective configuration.  Unset
+// flags are left out.  ND values are the (non-nil) pointers, ZV values
+// the values themselves.
+func (ndf *NDFlagSet) SetValues() map[string]interface{} {
+	m := make(map[string]interface{})
+	ndf.VisitSet(func(name string, value interface{}) {
+		m[name] = value
+	})
+	return m
+}
+
+// SetValuesWithPrefix - SetValues, limited to the flags whose names start
+// with prefix, e.g. all the db. flags for handing to the db code.  With
+// strip the prefix is cut from the keys, so -db.host is keyed host.
+func (ndf *NDFlagSet) SetValuesWithPrefix(prefix string, strip bool) map[string]interface{} {
+	m := make(map[string]interface{})
+	ndf.VisitSet(func(name string, value interface{}) {
+		if !strings.HasPrefix(name, prefix) {
+			return
+		}
+		if strip {
+			name = strings.TrimPrefix(name, prefix)
+		}
+		m[name] = value
+	})
+	return m
+}
+
+// VisitSet - calls fn in lexicographical order with the name and Get()
+// result of every flag that was set, see SetValues for what the values
+// look like.  A flag counts as set if IsSet says so and, for the ND
+// variants, its pointer isn't nil, so unset flags are never passed to fn.
+// Aliases are skipped, the canonical name is used.
+func (ndf *NDFlagSet) VisitSet(fn func(name string, value interface{})) {
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		v, ok := ndf.getSet(fl.Name)
+		if !ok {
+			return
+		}
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return
+		}
+		fn(fl.Name, v)
+	})
+}
+
+// Names - the sorted names of every flag defined, set or not, aliases
+// included, e.g. to check them against documented config keys.
+func (ndf *NDFlagSet) Names() []string {
+	var names []string
+	ndf.VisitAll(func(fl *flag.Flag) {
+		names = append(names, fl.Name)
+	})
+	return names
+}
+
+// Example - returns the example the named flag was defined with, as shown
+// in the usage, or "", false if there's no such flag.  Flags defined
+// without one, like the slices, return "", true.
+func (ndf *NDFlagSet) Example(name string) (string, bool) {
+	fl := ndf.Lookup(name)
+	if fl == nil {
+		return "", false
+	}
+	return fl.DefValue, true
+}
+
+// defaultParser - implemented by the scalar Value types, returns the
+// default they were defined with, or else the example parsed as if it
+// had been passed on the command line.
+type defaultParser interface {
+	parsedDefault() (interface{}, bool)
+}
+
+// typedDefault - the parsed default of the named flag as a T.
+func typedDefault[T any](ndf *NDFlagSet, name string) (T, bool) {
+	var zero T
+	fl := ndf.Lookup(name)
+	if fl == nil {
+		return zero, false
+	}
+	d, ok := unwrapValue(fl.Value).(defaultParser)
+	if !ok {
+		return zero, false
+	}
+	v, ok := d.parsedDefault()
+	if !ok {
+		return zero, false
+	}
+	t, ok := v.(T)
+	return t, ok
+}
+
+// DefaultString - returns the default of the named string flag, whether
+// or not it was set: the default if it was defined with one of the
+// ND*Default or ZV*Default methods, otherwise its example, parsed the way
+// a value on the command line would be.  Returns ("", false) for unknown
+// flags, flags of another type, and examples that don't parse, e.g. an
+// empty one for an int flag.  Slices, maps and the like have no default,
+// nor has NDStringFunc, as only ResolveDefaults calls its default func.
+func (ndf *NDFlagSet) DefaultString(name string) (string, bool) {
+	return typedDefault[string](ndf, name)
+}
+
+// DefaultBool - bool version of DefaultString
+func (ndf *NDFlagSet) DefaultBool(name string) (bool, bool) {
+	return typedDefault[bool](ndf, name)
+}
+
+// DefaultInt - int version of DefaultString
+func (ndf *NDFlagSet) DefaultInt(name string) (int, bool) {
+	return typedDefault[int](ndf, name)
+}
+
+// DefaultInt64 - int64 version of DefaultString
+func (ndf *NDFlagSet) DefaultInt64(name string) (int64, bool) {
+	return typedDefault[int64](ndf, name)
+}
+
+// DefaultUint - uint version of DefaultString
+func (ndf *NDFlagSet) DefaultUint(name string) (uint, bool) {
+	return typedDefault[uint](ndf, name)
+}
+
+// DefaultUint64 - uint64 version of DefaultString
+func (ndf *NDFlagSet) DefaultUint64(name string) (uint64, bool) {
+	return typedDefault[uint64](ndf, name)
+}
+
+// DefaultFloat64 - float64 version of DefaultString
+func (ndf *NDFlagSet) DefaultFloat64(name string) (float64, bool) {
+	return typedDefault[float64](ndf, name)
+}
+
+// DefaultDuration - time.Duration version of DefaultString
+func (ndf *NDFlagSet) DefaultDuration(name string) (time.Duration, bool) {
+	return typedDefault[time.Duration](ndf, name)
+}
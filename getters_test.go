@@ -0,0 +1,394 @@
+package nodefflag
+
+import (
+	"flag"
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestTypedValues(t *testing.T) {
+	for _, fs := range []*NDFlagSet{nfs(), zvs()} {
+		if _, ok := fs.StringValue("test_string"); ok {
+			t.Error("unset test_string should report not set")
+		}
+
+		_ = fs.Set("test_bool", "false")
+		_ = fs.Set("test_int", "42")
+		_ = fs.Set("test_int64", "-420")
+		_ = fs.Set("test_uint", "80")
+		_ = fs.Set("test_uint64", "800")
+		_ = fs.Set("test_string", "your ad here")
+		_ = fs.Set("test_float64", "123.45")
+		_ = fs.Set("test_duration", "30s")
+
+		if v, ok := fs.BoolValue("test_bool"); !ok || v != false {
+			t.Errorf("bad test_bool: %v %v", v, ok)
+		}
+		if v, ok := fs.IntValue("test_int"); !ok || v != 42 {
+			t.Errorf("bad test_int: %v %v", v, ok)
+		}
+		if v, ok := fs.Int64Value("test_int64"); !ok || v != -420 {
+			t.Errorf("bad test_int64: %v %v", v, ok)
+		}
+		if v, ok := fs.UintValue("test_uint"); !ok || v != 80 {
+			t.Errorf("bad test_uint: %v %v", v, ok)
+		}
+		if v, ok := fs.Uint64Value("test_uint64"); !ok || v != 800 {
+			t.Errorf("bad test_uint64: %v %v", v, ok)
+		}
+		if v, ok := fs.StringValue("test_string"); !ok || v != "your ad here" {
+			t.Errorf("bad test_string: %v %v", v, ok)
+		}
+		if v, ok := fs.Float64Value("test_float64"); !ok || v != 123.45 {
+			t.Errorf("bad test_float64: %v %v", v, ok)
+		}
+		if v, ok := fs.DurationValue("test_duration"); !ok || v != 30*time.Second {
+			t.Errorf("bad test_duration: %v %v", v, ok)
+		}
+
+		// wrong type and unknown names
+		if v, ok := fs.StringValue("test_int"); ok || v != "" {
+			t.Errorf("expected test_int to not be a string: %v %v", v, ok)
+		}
+		if v, ok := fs.IntValue("nope"); ok || v != 0 {
+			t.Errorf("expected unknown flag to report not set: %v %v", v, ok)
+		}
+	}
+}
+
+func TestTypedValuesSized(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt8("i8", 0, "")
+	fs.ZVInt16("i16", 0, "")
+	fs.NDInt32("i32", 0, "")
+	fs.ZVUint8("ui8", 0, "")
+	fs.NDUint16("ui16", 0, "")
+	fs.ZVUint32("ui32", 0, "")
+	fs.NDFloat32("f32", 0, "")
+	fs.ZVTime("time", time.Time{}, "")
+	fs.NDIP("ip", nil, "")
+	fs.ZVIPNet("ipnet", net.IPNet{}, "")
+	fs.NDURL("url", nil, "")
+
+	if _, ok := fs.IPNetValue("ipnet"); ok {
+		t.Error("unset ipnet should report not set")
+	}
+
+	err := fs.Parse([]string{
+		"-i8=-8", "-i16=-16", "-i32=-32", "-ui8=8", "-ui16=16", "-ui32=32",
+		"-f32=1.5", "-time=2023-01-15T09:30:00Z", "-ip=10.0.0.1",
+		"-ipnet=10.0.0.0/8", "-url=https://example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := fs.Int8Value("i8"); !ok || v != -8 {
+		t.Errorf("bad i8: %v %v", v, ok)
+	}
+	if v, ok := fs.Int16Value("i16"); !ok || v != -16 {
+		t.Errorf("bad i16: %v %v", v, ok)
+	}
+	if v, ok := fs.Int32Value("i32"); !ok || v != -32 {
+		t.Errorf("bad i32: %v %v", v, ok)
+	}
+	if v, ok := fs.Uint8Value("ui8"); !ok || v != 8 {
+		t.Errorf("bad ui8: %v %v", v, ok)
+	}
+	if v, ok := fs.Uint16Value("ui16"); !ok || v != 16 {
+		t.Errorf("bad ui16: %v %v", v, ok)
+	}
+	if v, ok := fs.Uint32Value("ui32"); !ok || v != 32 {
+		t.Errorf("bad ui32: %v %v", v, ok)
+	}
+	if v, ok := fs.Float32Value("f32"); !ok || v != 1.5 {
+		t.Errorf("bad f32: %v %v", v, ok)
+	}
+	if v, ok := fs.TimeValue("time"); !ok || v.Unix() != 1673775000 {
+		t.Errorf("bad time: %v %v", v, ok)
+	}
+	if v, ok := fs.IPValue("ip"); !ok || v.String() != "10.0.0.1" {
+		t.Errorf("bad ip: %v %v", v, ok)
+	}
+	if v, ok := fs.IPNetValue("ipnet"); !ok || v.String() != "10.0.0.0/8" {
+		t.Errorf("bad ipnet: %v %v", v, ok)
+	}
+	if v, ok := fs.URLValue("url"); !ok || v.Host != "example.com" {
+		t.Errorf("bad url: %v %v", v, ok)
+	}
+}
+
+func TestSetValues(t *testing.T) {
+	fs := nfs()
+	fs.ZVInt("zv_int", 0, "int value")
+	fs.ZVString("zv_string", "", "string value")
+
+	if m := fs.SetValues(); len(m) != 0 {
+		t.Errorf("expected no set values, got %v", m)
+	}
+
+	err := fs.Parse([]string{"-test_int=42", "-test_duration=1s", "-zv_int=0", "-test_string="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := fs.SetValues()
+	if len(m) != 4 {
+		t.Errorf("expected 4 set values, got %v", m)
+	}
+	if v, ok := m["test_int"].(*int); !ok || *v != 42 {
+		t.Errorf("bad test_int: %#v", m["test_int"])
+	}
+	if v, ok := m["test_duration"].(*time.Duration); !ok || *v != time.Second {
+		t.Errorf("bad test_duration: %#v", m["test_duration"])
+	}
+	if v, ok := m["test_string"].(*string); !ok || *v != "" {
+		t.Errorf("bad test_string: %#v", m["test_string"])
+	}
+	if v, ok := m["zv_int"]; !ok || v != 0 {
+		t.Errorf("bad zv_int: %#v", m["zv_int"])
+	}
+	for _, name := range []string{"test_bool", "test_uint", "zv_string"} {
+		if _, ok := m[name]; ok {
+			t.Errorf("unset %s should not be included", name)
+		}
+	}
+}
+
+func TestVisitSet(t *testing.T) {
+	fs := nfs()
+	fs.ZVInt("zv_int", 0, "int value")
+	fs.ZVString("zv_string", "", "string value")
+	fs.ZVStringSlice("zv_slice", "slice value")
+	ndIP := fs.NDIP("nd_ip", nil, "ip value")
+	fs.Alias("zv_int", "i")
+
+	visit := func() map[string]interface{} {
+		m := make(map[string]interface{})
+		var names []string
+		fs.VisitSet(func(name string, value interface{}) {
+			m[name] = value
+			names = append(names, name)
+		})
+		if !sort.StringsAreSorted(names) {
+			t.Errorf("not visited in order: %v", names)
+		}
+		return m
+	}
+
+	if m := visit(); len(m) != 0 {
+		t.Errorf("expected nothing visited, got %v", m)
+	}
+
+	err := fs.Parse([]string{"-test_bool=false", "-i=0", "-zv_slice=a", "-nd_ip=10.0.0.1", "-test_float64=2.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := visit()
+	if len(m) != 5 {
+		t.Errorf("expected 5 visited, got %v", m)
+	}
+	if v, ok := m["test_bool"].(*bool); !ok || *v {
+		t.Errorf("bad test_bool: %#v", m["test_bool"])
+	}
+	if v, ok := m["test_float64"].(*float64); !ok || *v != 2.5 {
+		t.Errorf("bad test_float64: %#v", m["test_float64"])
+	}
+	if v, ok := m["zv_int"]; !ok || v != 0 {
+		t.Errorf("bad zv_int: %#v", m["zv_int"])
+	}
+	if v, ok := m["zv_slice"].([]string); !ok || len(v) != 1 || v[0] != "a" {
+		t.Errorf("bad zv_slice: %#v", m["zv_slice"])
+	}
+	if v, ok := m["nd_ip"].(*net.IP); !ok || v.String() != "10.0.0.1" {
+		t.Errorf("bad nd_ip: %#v", m["nd_ip"])
+	}
+	for _, name := range []string{"i", "test_int", "zv_string"} {
+		if _, ok := m[name]; ok {
+			t.Errorf("%s should not be visited", name)
+		}
+	}
+
+	// an ND pointer cleared by hand no longer counts
+	*ndIP = nil
+	if _, ok := visit()["nd_ip"]; ok {
+		t.Error("nil nd_ip should not be visited")
+	}
+}
+
+func TestSetValuesWithPrefix(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("db.host", "", "db host")
+	fs.NDInt("db.port", 0, "db port")
+	fs.ZVString("db.user", "", "db user")
+	fs.NDString("dbx", "", "not a db flag")
+	fs.NDString("cache.host", "", "cache host")
+
+	if m := fs.SetValuesWithPrefix("db.", false); len(m) != 0 {
+		t.Errorf("expected no values before parse, got %v", m)
+	}
+	err := fs.Parse([]string{"-db.host=h", "-db.port=5432", "-dbx=x", "-cache.host=c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := fs.SetValuesWithPrefix("db.", false)
+	if len(m) != 2 {
+		t.Errorf("expected 2 values, got %v", m)
+	}
+	if v, ok := m["db.host"].(*string); !ok || *v != "h" {
+		t.Errorf("bad db.host: %#v", m["db.host"])
+	}
+
+	m = fs.SetValuesWithPrefix("db.", true)
+	if len(m) != 2 {
+		t.Errorf("expected 2 values, got %v", m)
+	}
+	if v, ok := m["port"].(*int); !ok || *v != 5432 {
+		t.Errorf("bad port: %#v", m["port"])
+	}
+	if _, ok := m["user"]; ok {
+		t.Error("unset db.user should be left out")
+	}
+
+	if m := fs.SetValuesWithPrefix("nope.", true); len(m) != 0 {
+		t.Errorf("expected no values, got %v", m)
+	}
+	if m := fs.SetValuesWithPrefix("", false); len(m) != 4 {
+		t.Errorf("empty prefix should match every set flag, got %v", m)
+	}
+}
+
+func TestExample(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("port", 8080, "port")
+	fs.ZVString("name", "anon", "name")
+	fs.NDDuration("timeout", 90*time.Second, "timeout")
+	fs.ZVCSVString("hosts", []string{"a", "b"}, "hosts")
+	fs.NDStringSlice("tag", "tags")
+	fs.Alias("port", "p")
+
+	tests := []struct {
+		name, want string
+		ok         bool
+	}{
+		{"port", "8080", true},
+		{"p", "8080", true},
+		{"name", "anon", true},
+		{"timeout", "1m30s", true},
+		{"hosts", "a,b", true},
+		{"tag", "", true},
+		{"nope", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := fs.Example(tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %q, %v", tt.name, got, ok)
+		}
+	}
+
+	// setting the flag doesn't change its example
+	if err := fs.Parse([]string{"-port=1", "-name=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := fs.Example("port"); got != "8080" {
+		t.Errorf("example changed after parse: %q", got)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("port", 8080, "port")
+	fs.ZVString("name", "anon", "name")
+	fs.NDDuration("timeout", 90*time.Second, "timeout")
+	fs.ZVFloat64("ratio", 0.5, "ratio")
+	fs.NDUint64Default("max", 10, "max")
+	fs.NDStringSlice("tag", "tags")
+
+	if err := fs.Parse([]string{"-port=1", "-name=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := fs.DefaultInt("port"); v != 8080 || !ok {
+		t.Errorf("port: got %v, %v", v, ok)
+	}
+	if v, ok := fs.DefaultString("name"); v != "anon" || !ok {
+		t.Errorf("name: got %q, %v", v, ok)
+	}
+	if v, ok := fs.DefaultDuration("timeout"); v != 90*time.Second || !ok {
+		t.Errorf("timeout: got %v, %v", v, ok)
+	}
+	if v, ok := fs.DefaultFloat64("ratio"); v != 0.5 || !ok {
+		t.Errorf("ratio: got %v, %v", v, ok)
+	}
+	if v, ok := fs.DefaultUint64("max"); v != 10 || !ok {
+		t.Errorf("max: got %v, %v", v, ok)
+	}
+	if _, ok := fs.DefaultString("port"); ok {
+		t.Error("port is not a string flag")
+	}
+	if _, ok := fs.DefaultInt("tag"); ok {
+		t.Error("slices have no default")
+	}
+	if _, ok := fs.DefaultInt("nope"); ok {
+		t.Error("unknown flags have no default")
+	}
+}
+
+func TestSliceValues(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDStringSlice("tag", "repeatable")
+	fs.ZVIntSlice("port", "repeatable")
+	fs.NDFloat64Slice("weight", "repeatable")
+	fs.ZVDurationSlice("wait", "repeatable")
+	fs.NDCSVString("csv", nil, "comma separated")
+	fs.ZVStringSlice("unset", "repeatable")
+
+	err := fs.Parse([]string{"-tag=a", "-tag=b", "-port=1", "-port=2", "-weight=0.5", "-wait=1s", "-csv="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := fs.StringSliceValue("tag"); !ok || !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("bad tag: %v, %v", v, ok)
+	}
+	if v, ok := fs.IntSliceValue("port"); !ok || !reflect.DeepEqual(v, []int{1, 2}) {
+		t.Errorf("bad port: %v, %v", v, ok)
+	}
+	if v, ok := fs.Float64SliceValue("weight"); !ok || !reflect.DeepEqual(v, []float64{0.5}) {
+		t.Errorf("bad weight: %v, %v", v, ok)
+	}
+	if v, ok := fs.DurationSliceValue("wait"); !ok || !reflect.DeepEqual(v, []time.Duration{time.Second}) {
+		t.Errorf("bad wait: %v, %v", v, ok)
+	}
+	if v, ok := fs.StringSliceValue("csv"); !ok || len(v) != 0 {
+		t.Errorf("empty csv should be set and empty: %v, %v", v, ok)
+	}
+	for _, name := range []string{"unset", "nope"} {
+		if v, ok := fs.StringSliceValue(name); ok || v != nil {
+			t.Errorf("%s should be nil, false: %v, %v", name, v, ok)
+		}
+	}
+	if v, ok := fs.IntSliceValue("tag"); ok || v != nil {
+		t.Errorf("wrong type should be nil, false: %v, %v", v, ok)
+	}
+}
+
+func TestNames(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	if n := fs.Names(); len(n) != 0 {
+		t.Errorf("no flags yet, got %v", n)
+	}
+	fs.NDString("zone", "", "zone")
+	fs.ZVInt("port", 0, "port")
+	fs.NDBoolNegatable("cache", false, "cache")
+	fs.Alias("port", "p")
+	fs.FlagSet.String("std", "", "plain flag")
+
+	if err := fs.Parse([]string{"-port=1"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"cache", "no-cache", "p", "port", "std", "zone"}
+	if got := fs.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package nodefflag
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrHelp - what the Parse methods return when help was asked for, the
+// flag package's ErrHelp, so either can be compared against.
+var ErrHelp = flag.ErrHelp
+
+// helpf - the Value behind EnableHelp, parsing never gets as far as
+// setting it.
+type helpf struct{}
+
+func (h helpf) String() string {
+	return ""
+}
+
+func (h helpf) Set(val string) error {
+	_, err := strconv.ParseBool(val)
+	return err
+}
+
+func (h helpf) IsBoolFlag() bool {
+	return true
+}
+
+// EnableHelp - defines -help, with -h as its alias, so they're listed in
+// the usage.  Given either, nothing is parsed: the Parse methods print
+// the usage to the output and return ErrHelp, even if later arguments are
+// bad.  With ExitOnError that's exit status 0 instead, PanicOnError
+// panics with ErrHelp.  Without EnableHelp the flag package does much the
+// same for an undefined -h or -help, this makes it explicit.  A name
+// that's already defined is left alone.
+func (ndf *NDFlagSet) EnableHelp() {
+	for _, name := range []string{"help", "h"} {
+		switch {
+		case ndf.Lookup(name) != nil:
+		case ndf.helpName == "":
+			ndf.Var(helpf{}, name, "show this help")
+			ndf.helpName = name
+		default:
+			ndf.Alias(ndf.helpName, name)
+		}
+	}
+}
+
+// helpRequested - whether args ask for help with the EnableHelp flag,
+// walking them as the flag package would.  If they do, nothing is parsed,
+// so the output isn't cluttered with errors from the other arguments.
+func (ndf *NDFlagSet) helpRequested(args []string) bool {
+	if ndf.helpName == "" {
+		return false
+	}
+	for i := 0; i < len(args); i++ {
+		s := args[i]
+		if len(s) < 2 || s[0] != '-' || s == "--" {
+			return false
+		}
+		name := strings.TrimPrefix(s[1:], "-")
+		value, hasValue := "", false
+		if j := strings.Index(name, "="); j >= 0 {
+			name, value, hasValue = name[:j], name[j+1:], true
+		}
+		fl := ndf.Lookup(name)
+		if fl == nil {
+			return false
+		}
+		if _, ok := unwrapValue(fl.Value).(helpf); ok {
+			b, err := strconv.ParseBool(value)
+			return !hasValue || (err == nil && b)
+		}
+		b, ok := fl.Value.(interface {
+			IsBoolFlag() bool
+		})
+		if !hasValue && (!ok || !b.IsBoolFlag()) {
+			// skip the value, it's not a flag
+			i++
+		}
+	}
+	return false
+}
+
+// help - prints the usage and returns ErrHelp, as the flag package does.
+func (ndf *NDFlagSet) help() error {
+	ndf.FlagSet.Usage()
+	switch ndf.ErrorHandling() {
+	case flag.ExitOnError:
+		os.Exit(0)
+	case flag.PanicOnError:
+		panic(ErrHelp)
+	}
+	return ErrHelp
+}
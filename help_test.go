@@ -0,0 +1,47 @@
+package nodefflag
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestEnableHelp(t *testing.T) {
+	newFS := func() (*NDFlagSet, *bytes.Buffer) {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		buf := &bytes.Buffer{}
+		fs.SetOutput(buf)
+		fs.NDString("name", "", "string value")
+		fs.ZVInt("port", 0, "int value")
+		fs.EnableHelp()
+		return fs, buf
+	}
+
+	for _, args := range [][]string{
+		{"-h"},
+		{"--help"},
+		{"-name", "-h", "-help=true"},
+		{"-port=1", "-h", "-port=x", "-nope"},
+	} {
+		fs, buf := newFS()
+		if err := fs.Parse(args); err != ErrHelp || err != flag.ErrHelp {
+			t.Errorf("%v: expected ErrHelp, got %v", args, err)
+		}
+		out := buf.String()
+		if !strings.HasPrefix(out, "Usage of NDflag_test:\n") || !strings.Contains(out, "-help, -h") {
+			t.Errorf("%v: expected just the usage, got:\n%s", args, out)
+		}
+	}
+
+	fs, buf := newFS()
+	if err := fs.Parse([]string{"-name", "-h", "-help=false"}); err != nil {
+		t.Errorf("-help=false shouldn't ask for help: %v", err)
+	}
+	if v, _ := fs.StringValue("name"); v != "-h" || buf.Len() != 0 {
+		t.Errorf("-h as a value isn't a request for help: %q, %q", v, buf)
+	}
+	if err := fs.ParseWithEnv([]string{"-help"}); err != ErrHelp {
+		t.Errorf("ParseWithEnv should honor help too, got %v", err)
+	}
+}
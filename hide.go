@@ -0,0 +1,33 @@
+package nodefflag
+
+// Hide - leaves the named flag out of the usage, UsageJSON and
+// BashCompletion, e.g. for internal or experimental flags.  It still
+// parses as usual.  Hiding a negatable bool hides its -no- half as well,
+// and aliases go with their flag.
+func (ndf *NDFlagSet) Hide(name string) {
+	if c, ok := ndf.aliases[name]; ok {
+		name = c
+	}
+	if ndf.hidden == nil {
+		ndf.hidden = make(map[string]bool)
+	}
+	ndf.hidden[name] = true
+	if neg := ndf.negationOf(name); neg != "" {
+		ndf.hidden[neg] = true
+	}
+}
+
+// SetShowHidden - with on, hidden flags are listed like any other, e.g.
+// behind a debug switch.
+func (ndf *NDFlagSet) SetShowHidden(on bool) {
+	ndf.showHidden = on
+}
+
+// isHidden - whether the named flag, or the one it's an alias of, is
+// left out of the usage.
+func (ndf *NDFlagSet) isHidden(name string) bool {
+	if c, ok := ndf.aliases[name]; ok {
+		name = c
+	}
+	return ndf.hidden[name] && !ndf.showHidden
+}
@@ -0,0 +1,139 @@
+package nodefflag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Source - somewhere ParseLayered can find flag values, keyed by the
+// flag's name.
+type Source interface {
+	Lookup(name string) (string, bool)
+}
+
+// multiSource - a Source that may hold several values for a flag, which
+// are Set in turn, e.g. a JSON array for a slice flag.
+type multiSource interface {
+	lookupAll(name string) ([]string, bool)
+}
+
+type envSource string
+
+// EnvSource - a Source reading the environment, with variable names built
+// as for EnvName using prefix.
+func EnvSource(prefix string) Source {
+	return envSource(prefix)
+}
+
+func (s envSource) Lookup(name string) (string, bool) {
+	return os.LookupEnv(envName(string(s), name))
+}
+
+type mapSource map[string]string
+
+// MapSource - a Source backed by m.
+func MapSource(m map[string]string) Source {
+	return mapSource(m)
+}
+
+func (s mapSource) Lookup(name string) (string, bool) {
+	v, ok := s[name]
+	return v, ok
+}
+
+type jsonSource map[string][]string
+
+// JSONFileSource - a Source backed by the JSON object in path, which is
+// read right away.  Values are converted as for ParseWithConfig, and null
+// counts as absent.  Unlike ParseWithConfig, keys that don't match a flag
+// are ignored, as they may be meant for another layer.
+func JSONFileSource(path string) (Source, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	var cfg map[string]json.RawMessage
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %v", path, err)
+	}
+	s := make(jsonSource, len(cfg))
+	for k, raw := range cfg {
+		vals, err := configStrings(raw)
+		if err != nil {
+			return nil, fmt.Errorf("config %s: bad value for %q: %v", path, k, err)
+		}
+		if vals != nil {
+			s[k] = vals
+		}
+	}
+	return s, nil
+}
+
+func (s jsonSource) Lookup(name string) (string, bool) {
+	vals, ok := s[name]
+	if !ok {
+		return "", false
+	}
+	return vals[len(vals)-1], true
+}
+
+func (s jsonSource) lookupAll(name string) ([]string, bool) {
+	vals, ok := s[name]
+	return vals, ok
+}
+
+// ParseLayered - parses args, then any flag still unset is set from the
+// first of sources that has a value for it, so precedence is command
+// line, then sources in order, then unset.  An ND flag's double pointer
+// only becomes non-nil if some layer provides it.  For a simple case see
+// ParseWithEnv or ParseWithConfig, which this generalizes.
+func (ndf *NDFlagSet) ParseLayered(args []string, sources ...Source) error {
+	if err := ndf.parseArgs(args); err != nil {
+		return err
+	}
+	if err := ndf.applySources(sources); err != nil {
+		return ndf.fail(err)
+	}
+	return ndf.postParse()
+}
+
+func (ndf *NDFlagSet) applySources(sources []Source) error {
+	var names []string
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		if !ndf.IsSet(fl.Name) {
+			names = append(names, fl.Name)
+		}
+	})
+	sort.Strings(names)
+
+	for _, name := range names {
+		for i, src := range sources {
+			vals, ok := sourceValues(src, name)
+			if !ok {
+				continue
+			}
+			for _, v := range vals {
+				if err := ndf.Set(name, v); err != nil {
+					return fmt.Errorf("source %d: invalid value %q for flag -%s: %v", i+1, v, name, err)
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// sourceValues - the values src has for name, using lookupAll if it can.
+func sourceValues(src Source, name string) ([]string, bool) {
+	if ms, ok := src.(multiSource); ok {
+		return ms.lookupAll(name)
+	}
+	v, ok := src.Lookup(name)
+	if !ok {
+		return nil, false
+	}
+	return []string{v}, true
+}
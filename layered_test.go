@@ -0,0 +1,102 @@
+package nodefflag
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLayered(t *testing.T) {
+	setenv(t, "NDLAYER_ENV", "env")
+	setenv(t, "NDLAYER_ALL", "env")
+	setenv(t, "NDLAYER_CLI", "env")
+	defer func() {
+		for _, k := range []string{"NDLAYER_ENV", "NDLAYER_ALL", "NDLAYER_CLI"} {
+			os.Unsetenv(k)
+		}
+	}()
+
+	dir, err := ioutil.TempDir("", "nodefflag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	cfg := `{"all": "json", "json": "json", "null": null, "list": ["a", "b"], "other": 1}`
+	if err := ioutil.WriteFile(path, []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	js, err := JSONFileSource(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	cli := fs.NDString("cli", "", "string value")
+	all := fs.NDString("all", "", "string value")
+	env := fs.NDString("env", "", "string value")
+	jsonv := fs.NDString("json", "", "string value")
+	mapv := fs.ZVString("map", "", "string value")
+	null := fs.NDString("null", "", "string value")
+	none := fs.NDString("none", "", "string value")
+	list := fs.NDStringSlice("list", "slice value")
+
+	m := MapSource(map[string]string{"all": "map", "json": "map", "map": "map", "null": "map"})
+	if err := fs.ParseLayered([]string{"-cli=cli"}, EnvSource("NDLAYER_"), js, m); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		got  **string
+		want string
+	}{
+		"cli":  {cli, "cli"},
+		"all":  {all, "env"},
+		"env":  {env, "env"},
+		"json": {jsonv, "json"},
+		"null": {null, "map"},
+	} {
+		if *tc.got == nil {
+			t.Errorf("-%s should be set", name)
+		} else if **tc.got != tc.want {
+			t.Errorf("-%s: got %q, want %q", name, **tc.got, tc.want)
+		}
+	}
+	if *mapv != "map" {
+		t.Errorf("-map: got %q", *mapv)
+	}
+	if *none != nil {
+		t.Errorf("-none should be unset, got %q", **none)
+	}
+	if *list == nil || !reflect.DeepEqual(**list, []string{"a", "b"}) {
+		t.Errorf("-list: got %v", *list)
+	}
+
+	fs = NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDInt("all", 0, "int value")
+	if err := fs.ParseLayered(nil, MapSource(map[string]string{"all": "x"})); err == nil {
+		t.Errorf("expected an error for a bad value")
+	}
+}
+
+func TestParseLayeredDeprecated(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	out := &strings.Builder{}
+	fs.SetOutput(out)
+	fs.NDString("legacy", "", "legacy")
+	fs.NDString("other", "", "other")
+	fs.Deprecate("legacy", "drop it")
+	fs.Deprecate("other", "unused")
+
+	if err := fs.ParseLayered(nil, MapSource(map[string]string{"legacy": "x"})); err != nil {
+		t.Fatal(err)
+	}
+	if want := "flag -legacy is deprecated: drop it\n"; out.String() != want {
+		t.Errorf("setting from a source should warn, got %q", out.String())
+	}
+}
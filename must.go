@@ -0,0 +1,138 @@
+package nodefflag
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// must - the body of the Must getters, panics unless ok.
+func must[T any](ndf *NDFlagSet, name string, v T, ok bool) T {
+	if ok {
+		return v
+	}
+	if ndf.Lookup(name) == nil {
+		panic(fmt.Sprintf("nodefflag: flag -%s is not defined", name))
+	}
+	if !ndf.IsSet(name) {
+		panic(fmt.Sprintf("nodefflag: flag -%s was not set", name))
+	}
+	panic(fmt.Sprintf("nodefflag: flag -%s does not hold a %T", name, v))
+}
+
+// MustString - same as StringValue, but panics if the flag wasn't set,
+// for startup code where a missing flag is fatal anyway.  The panic says
+// whether the flag was not set, not defined, or is of another type.
+func (ndf *NDFlagSet) MustString(name string) string {
+	v, ok := ndf.StringValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustBool - bool version of MustString
+func (ndf *NDFlagSet) MustBool(name string) bool {
+	v, ok := ndf.BoolValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustInt - int version of MustString
+func (ndf *NDFlagSet) MustInt(name string) int {
+	v, ok := ndf.IntValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustInt8 - int8 version of MustString
+func (ndf *NDFlagSet) MustInt8(name string) int8 {
+	v, ok := ndf.Int8Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustInt16 - int16 version of MustString
+func (ndf *NDFlagSet) MustInt16(name string) int16 {
+	v, ok := ndf.Int16Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustInt32 - int32 version of MustString
+func (ndf *NDFlagSet) MustInt32(name string) int32 {
+	v, ok := ndf.Int32Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustInt64 - int64 version of MustString
+func (ndf *NDFlagSet) MustInt64(name string) int64 {
+	v, ok := ndf.Int64Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustUint - uint version of MustString
+func (ndf *NDFlagSet) MustUint(name string) uint {
+	v, ok := ndf.UintValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustUint8 - uint8 version of MustString
+func (ndf *NDFlagSet) MustUint8(name string) uint8 {
+	v, ok := ndf.Uint8Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustUint16 - uint16 version of MustString
+func (ndf *NDFlagSet) MustUint16(name string) uint16 {
+	v, ok := ndf.Uint16Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustUint32 - uint32 version of MustString
+func (ndf *NDFlagSet) MustUint32(name string) uint32 {
+	v, ok := ndf.Uint32Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustUint64 - uint64 version of MustString
+func (ndf *NDFlagSet) MustUint64(name string) uint64 {
+	v, ok := ndf.Uint64Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustFloat32 - float32 version of MustString
+func (ndf *NDFlagSet) MustFloat32(name string) float32 {
+	v, ok := ndf.Float32Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustFloat64 - float64 version of MustString
+func (ndf *NDFlagSet) MustFloat64(name string) float64 {
+	v, ok := ndf.Float64Value(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustDuration - time.Duration version of MustString
+func (ndf *NDFlagSet) MustDuration(name string) time.Duration {
+	v, ok := ndf.DurationValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustTime - time.Time version of MustString
+func (ndf *NDFlagSet) MustTime(name string) time.Time {
+	v, ok := ndf.TimeValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustIP - net.IP version of MustString
+func (ndf *NDFlagSet) MustIP(name string) net.IP {
+	v, ok := ndf.IPValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustIPNet - net.IPNet version of MustString
+func (ndf *NDFlagSet) MustIPNet(name string) *net.IPNet {
+	v, ok := ndf.IPNetValue(name)
+	return must(ndf, name, v, ok)
+}
+
+// MustURL - url.URL version of MustString
+func (ndf *NDFlagSet) MustURL(name string) *url.URL {
+	v, ok := ndf.URLValue(name)
+	return must(ndf, name, v, ok)
+}
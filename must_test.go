@@ -0,0 +1,47 @@
+package nodefflag
+
+import (
+	"flag"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMust(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("name", "", "name")
+	fs.ZVInt("port", 0, "port")
+	fs.NDDuration("timeout", 0, "timeout")
+	fs.NDIPNet("net", net.IPNet{}, "network")
+	fs.NDString("unset", "", "never given")
+
+	if err := fs.Parse([]string{"-name=x", "-port=0", "-timeout=1s", "-net=10.0.0.0/8"}); err != nil {
+		t.Fatal(err)
+	}
+	if v := fs.MustString("name"); v != "x" {
+		t.Errorf("bad name: %q", v)
+	}
+	if v := fs.MustInt("port"); v != 0 {
+		t.Errorf("bad port: %d", v)
+	}
+	if v := fs.MustDuration("timeout"); v != time.Second {
+		t.Errorf("bad timeout: %v", v)
+	}
+	if v := fs.MustIPNet("net"); v.String() != "10.0.0.0/8" {
+		t.Errorf("bad net: %v", v)
+	}
+
+	mustPanic := func(want string, fn func()) {
+		t.Helper()
+		defer func() {
+			if got := recover(); got != want {
+				t.Errorf("got panic %v, want %q", got, want)
+			}
+		}()
+		fn()
+	}
+	mustPanic("nodefflag: flag -unset was not set", func() { fs.MustString("unset") })
+	mustPanic("nodefflag: flag -nope is not defined", func() { fs.MustBool("nope") })
+	mustPanic("nodefflag: flag -port does not hold a string", func() { fs.MustString("port") })
+	mustPanic("nodefflag: flag -name does not hold a *url.URL", func() { fs.MustURL("name") })
+}
@@ -0,0 +1,153 @@
+package nodefflag
+
+import "time"
+
+// The ND*Default methods are the ND methods with a real default rather
+// than just an example: the value is shown in the usage as the default,
+// and MaterializeDefaults points the target at it.  Until then the double
+// pointer still references nil if the flag wasn't set, as with any ND
+// flag, so you can tell a default from a value given.
+
+// markDefault - the second half of every ND*Default method, makes def the
+// default of the just registered flag.
+func markDefault[T any](ndf *NDFlagSet, name string, def T) {
+	if n, ok := ndf.tracked[name].Value.(*ndv[T]); ok {
+		n.def = &def
+	}
+}
+
+// NDValueDefault - NDValue with def as its default, see NDStringDefault.
+func NDValueDefault[T any](ndf *NDFlagSet, name string, parse func(string) (T, error), def T, usage string) **T {
+	var v *T
+	NDValueDefaultVar(ndf, &v, name, parse, def, usage)
+	return &v
+}
+
+// NDValueDefaultVar - BYO pp version of NDValueDefault
+func NDValueDefaultVar[T any](ndf *NDFlagSet, v **T, name string, parse func(string) (T, error), def T, usage string) {
+	NDValueVar(ndf, v, name, parse, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDStringDefault - NDString with def as its default: shown as such in
+// the usage, and what MaterializeDefaults sets the flag to if it wasn't
+// given.  The double pointer references nil until then.
+func (ndf *NDFlagSet) NDStringDefault(name string, def string, usage string) **string {
+	var sv *string
+	ndf.NDStringDefaultVar(&sv, name, def, usage)
+	return &sv
+}
+
+// NDStringDefaultVar - BYO pp version of NDStringDefault
+func (ndf *NDFlagSet) NDStringDefaultVar(sv **string, name string, def string, usage string) {
+	ndf.NDStringVar(sv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDBoolDefault - NDBool with def as its default, see NDStringDefault.
+func (ndf *NDFlagSet) NDBoolDefault(name string, def bool, usage string) **bool {
+	var bv *bool
+	ndf.NDBoolDefaultVar(&bv, name, def, usage)
+	return &bv
+}
+
+// NDBoolDefaultVar - BYO pp version of NDBoolDefault
+func (ndf *NDFlagSet) NDBoolDefaultVar(bv **bool, name string, def bool, usage string) {
+	ndf.NDBoolVar(bv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDIntDefault - NDInt with def as its default, see NDStringDefault.
+func (ndf *NDFlagSet) NDIntDefault(name string, def int, usage string) **int {
+	var iv *int
+	ndf.NDIntDefaultVar(&iv, name, def, usage)
+	return &iv
+}
+
+// NDIntDefaultVar - BYO pp version of NDIntDefault
+func (ndf *NDFlagSet) NDIntDefaultVar(iv **int, name string, def int, usage string) {
+	ndf.NDIntVar(iv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDInt64Default - NDInt64 with def as its default, see NDStringDefault.
+func (ndf *NDFlagSet) NDInt64Default(name string, def int64, usage string) **int64 {
+	var iv *int64
+	ndf.NDInt64DefaultVar(&iv, name, def, usage)
+	return &iv
+}
+
+// NDInt64DefaultVar - BYO pp version of NDInt64Default
+func (ndf *NDFlagSet) NDInt64DefaultVar(iv **int64, name string, def int64, usage string) {
+	ndf.NDInt64Var(iv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDUintDefault - NDUint with def as its default, see NDStringDefault.
+func (ndf *NDFlagSet) NDUintDefault(name string, def uint, usage string) **uint {
+	var uiv *uint
+	ndf.NDUintDefaultVar(&uiv, name, def, usage)
+	return &uiv
+}
+
+// NDUintDefaultVar - BYO pp version of NDUintDefault
+func (ndf *NDFlagSet) NDUintDefaultVar(uiv **uint, name string, def uint, usage string) {
+	ndf.NDUintVar(uiv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDUint64Default - NDUint64 with def as its default, see NDStringDefault.
+func (ndf *NDFlagSet) NDUint64Default(name string, def uint64, usage string) **uint64 {
+	var uiv *uint64
+	ndf.NDUint64DefaultVar(&uiv, name, def, usage)
+	return &uiv
+}
+
+// NDUint64DefaultVar - BYO pp version of NDUint64Default
+func (ndf *NDFlagSet) NDUint64DefaultVar(uiv **uint64, name string, def uint64, usage string) {
+	ndf.NDUint64Var(uiv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDFloat64Default - NDFloat64 with def as its default, see NDStringDefault.
+func (ndf *NDFlagSet) NDFloat64Default(name string, def float64, usage string) **float64 {
+	var fv *float64
+	ndf.NDFloat64DefaultVar(&fv, name, def, usage)
+	return &fv
+}
+
+// NDFloat64DefaultVar - BYO pp version of NDFloat64Default
+func (ndf *NDFlagSet) NDFloat64DefaultVar(fv **float64, name string, def float64, usage string) {
+	ndf.NDFloat64Var(fv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDDurationDefault - NDDuration with def as its default, see NDStringDefault.
+func (ndf *NDFlagSet) NDDurationDefault(name string, def time.Duration, usage string) **time.Duration {
+	var dv *time.Duration
+	ndf.NDDurationDefaultVar(&dv, name, def, usage)
+	return &dv
+}
+
+// NDDurationDefaultVar - BYO pp version of NDDurationDefault
+func (ndf *NDFlagSet) NDDurationDefaultVar(dv **time.Duration, name string, def time.Duration, usage string) {
+	ndf.NDDurationVar(dv, name, def, usage)
+	markDefault(ndf, name, def)
+}
+
+// NDStringFunc - string flag whose default is computed by defaultFn, e.g.
+// the hostname, only once it's needed: ResolveDefaults calls it if the
+// flag wasn't set.  The double pointer references nil until then, as
+// with any ND flag.  Nothing is shown for the default in the usage, as
+// that would mean calling defaultFn.
+func (ndf *NDFlagSet) NDStringFunc(name string, defaultFn func() string, usage string) **string {
+	var sv *string
+	ndf.NDStringFuncVar(&sv, name, defaultFn, usage)
+	return &sv
+}
+
+// NDStringFuncVar - BYO pp version of NDStringFunc
+func (ndf *NDFlagSet) NDStringFuncVar(sv **string, name string, defaultFn func() string, usage string) {
+	s := &ndv[string]{v: sv, parse: parseString, stdin: true, lazy: defaultFn}
+	ndf.Var(s, name, usage)
+}
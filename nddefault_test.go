@@ -0,0 +1,105 @@
+package nodefflag
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNDDefault(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	host := fs.NDStringDefault("host", "localhost", "host")
+	port := fs.NDIntDefault("port", 8080, "port")
+	wait := fs.NDDurationDefault("wait", 5*time.Second, "wait")
+	upper := func(s string) (string, error) { return strings.ToUpper(s), nil }
+	level := NDValueDefault(fs, "level", upper, "INFO", "level")
+	name := fs.NDString("name", "bob", "name")
+
+	out := usage(fs)
+	for _, want := range []string{
+		`host (default "localhost")`,
+		`port (default 8080)`,
+		`wait (default 5s)`,
+		`level (default INFO)`,
+		`name (example "bob")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("usage missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := fs.Parse([]string{"-port=9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if *host != nil || *wait != nil {
+		t.Errorf("defaults shouldn't be applied until materialized")
+	}
+	fs.MaterializeDefaults()
+	if *host == nil || **host != "localhost" {
+		t.Errorf("bad host: %v", *host)
+	}
+	if *port == nil || **port != 9090 {
+		t.Errorf("port was set, should be untouched: %v", *port)
+	}
+	if *wait == nil || **wait != 5*time.Second {
+		t.Errorf("bad wait: %v", *wait)
+	}
+	if *level == nil || **level != "INFO" {
+		t.Errorf("bad level: %v", *level)
+	}
+	if *name == nil || **name != "bob" {
+		t.Errorf("plain ND flags still materialize their example: %v", *name)
+	}
+	if fs.IsSet("host") {
+		t.Errorf("a default doesn't count as set")
+	}
+}
+
+func TestNDStringFunc(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	calls := map[string]int{}
+	lazy := func(name string) func() string {
+		return func() string {
+			calls[name]++
+			return "computed " + name
+		}
+	}
+	host := fs.NDStringFunc("host", lazy("host"), "host")
+	dir := fs.NDStringFunc("dir", lazy("dir"), "dir")
+
+	if d, ok := fs.DefaultString("dir"); ok {
+		t.Errorf("a default func has no default to report, got %q", d)
+	}
+	fs.SetOutput(ioutil.Discard)
+	fs.PrintDefaults()
+	if err := fs.UsageJSON(ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	fs.Dump()
+	if len(calls) != 0 {
+		t.Errorf("only ResolveDefaults should call the default funcs, got %v", calls)
+	}
+
+	if err := fs.Parse([]string{"-host", "given"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 0 || *dir != nil {
+		t.Errorf("default funcs should not run before ResolveDefaults, got %v", calls)
+	}
+	fs.ResolveDefaults()
+	if calls["host"] != 0 {
+		t.Error("the default func of a set flag should not be called")
+	}
+	if calls["dir"] != 1 || *dir == nil || **dir != "computed dir" {
+		t.Errorf("expected dir resolved once, got %v calls", calls["dir"])
+	}
+	if **host != "given" || fs.IsSet("dir") {
+		t.Errorf("resolving should not touch set flags or IsSet, got %q", **host)
+	}
+	fs.ResolveDefaults()
+	if calls["dir"] != 1 {
+		t.Error("an already resolved flag should not be resolved again")
+	}
+}
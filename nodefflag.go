@@ -2,196 +2,410 @@
 package nodefflag
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"image/color"
 	"io"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
-// implement the Value interface for flags
-type ndsf struct {
-	sv      **string
-	example string
+// CSVOptions - controls how the CSV flag variants split their value.
+// Splitting is a plain strings.Split on Sep, there is no csv style quoting.
+type CSVOptions struct {
+	// Sep is the field separator, "," if empty.
+	Sep string
+	// KeepEmpty keeps empty fields, by default they are dropped.
+	KeepEmpty bool
 }
 
-func (s *ndsf) String() string {
-	return s.example
+func (o CSVOptions) sep() string {
+	if o.Sep == "" {
+		return ","
+	}
+	return o.Sep
 }
 
-func (s *ndsf) Set(val string) error {
-	*s.sv = &val
-	return nil
+func (o CSVOptions) parse(val string) ([]string, error) {
+	fields := strings.Split(val, o.sep())
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" && !o.KeepEmpty {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out, nil
 }
 
-func (s *ndsf) Get() interface{} {
-	return *s.sv
+// EnumOptions - controls how the enum flag variants match their value.
+type EnumOptions struct {
+	// IgnoreCase matches choices case-insensitively.  The value stored
+	// is always spelled as it is in the choices.
+	IgnoreCase bool
 }
 
-type ndbf struct {
-	bv      **bool
-	example string
+// parser - returns a parse func that accepts only one of choices.
+func (o EnumOptions) parser(choices []string) func(string) (string, error) {
+	return func(val string) (string, error) {
+		for _, c := range choices {
+			if c == val || (o.IgnoreCase && strings.EqualFold(c, val)) {
+				return c, nil
+			}
+		}
+		return "", fmt.Errorf("invalid choice %q, must be one of: %s", val, strings.Join(choices, ", "))
+	}
 }
 
-func (b *ndbf) String() string {
-	return b.example
-}
+// PercentMode - what the percent flag variants do with values outside of
+// 0-100%.
+type PercentMode int
+
+const (
+	// PercentReject makes out of range values an error.
+	PercentReject PercentMode = iota
+	// PercentClamp moves out of range values to the nearest end.
+	PercentClamp
+	// PercentAllow takes any value, e.g. for 150% growth.
+	PercentAllow
+)
 
-func (b *ndbf) Set(val string) error {
-	pb, err := strconv.ParseBool(val)
+func (m PercentMode) parse(val string) (float64, error) {
+	s := strings.TrimSpace(val)
+	div := 1.0
+	if strings.HasSuffix(s, "%") {
+		s, div = strings.TrimSpace(s[:len(s)-1]), 100
+	}
+	f, err := parseFiniteFloat(s)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("invalid percentage %q", val)
 	}
-	*b.bv = &pb
-	return nil
+	f /= div
+	if (f >= 0 && f <= 1) || m == PercentAllow {
+		return f, nil
+	}
+	if m == PercentReject {
+		return 0, fmt.Errorf("percentage %q out of range 0-100%%", val)
+	}
+	return math.Max(0, math.Min(1, f)), nil
 }
 
-func (b *ndbf) Get() interface{} {
-	return *b.bv
-}
+// LogLevel - a log level, as taken by the log level flag variants.
+// Levels compare in order of severity, and Info is the zero value, so
+// that's what a ZV log level flag holds if unset.
+type LogLevel int
 
-func (b *ndbf) IsBoolFlag() bool {
-	return true
+// The log levels, least severe first.
+const (
+	LevelDebug LogLevel = iota - 1
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var logLevelNames = []string{"debug", "info", "warn", "error"}
+
+// String - the level's lower case name, e.g. "warn".
+func (l LogLevel) String() string {
+	if i := int(l - LevelDebug); i >= 0 && i < len(logLevelNames) {
+		return logLevelNames[i]
+	}
+	return "LogLevel(" + strconv.Itoa(int(l)) + ")"
 }
 
-type ndif struct {
-	iv      **int
-	example string
+func parseLogLevel(val string) (LogLevel, error) {
+	s := strings.ToLower(strings.TrimSpace(val))
+	if s == "warning" {
+		s = "warn"
+	}
+	for i, name := range logLevelNames {
+		if s == name {
+			return LevelDebug + LogLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, want one of %s", val, strings.Join(logLevelNames, "|"))
 }
 
-func (i *ndif) String() string {
-	return i.example
+// PathOptions - controls what the path flag variants accept.  The checks
+// run when the flag is set, so a bad path fails the parse.
+type PathOptions struct {
+	// MustExist rejects paths that don't exist.
+	MustExist bool
+	// Dir rejects anything but an existing directory.
+	Dir bool
+	// File rejects anything but an existing regular file.
+	File bool
+	// ExpandHome replaces a leading ~ with the user's home directory.
+	ExpandHome bool
 }
 
-func (i *ndif) Set(val string) error {
-	pi, err := strconv.Atoi(val)
-	if err != nil {
-		return err
+func (o PathOptions) parse(val string) (string, error) {
+	if o.ExpandHome && (val == "~" || strings.HasPrefix(val, "~/")) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		val = home + val[1:]
 	}
-	*i.iv = &pi
-	return nil
+	if !o.MustExist && !o.Dir && !o.File {
+		return val, nil
+	}
+	fi, err := os.Stat(val)
+	switch {
+	case os.IsNotExist(err):
+		return "", fmt.Errorf("path %q does not exist", val)
+	case err != nil:
+		return "", err
+	case o.Dir && !fi.IsDir():
+		return "", fmt.Errorf("path %q is not a directory", val)
+	case o.File && !fi.Mode().IsRegular():
+		return "", fmt.Errorf("path %q is not a regular file", val)
+	}
+	return val, nil
+}
+
+// ndenumf - string flag restricted to choices.
+type ndenumf struct {
+	*ndv[string]
+	choices []string
 }
 
-func (i *ndif) Get() interface{} {
-	return *i.iv
+func (e *ndenumf) choiceList() []string {
+	return e.choices
 }
 
-type ndi64f struct {
-	iv      **int64
+// negbf - the -no-name half of a negatable bool, it sets the positive
+// flag to the opposite of its own value.
+type negbf struct {
+	pos     flag.Value
 	example string
 }
 
-func (i *ndi64f) String() string {
-	return i.example
+func (n *negbf) String() string {
+	return n.example
 }
 
-func (i *ndi64f) Set(val string) error {
-	pi, err := strconv.ParseInt(val, 10, 64)
+func (n *negbf) Set(val string) error {
+	b, err := strconv.ParseBool(val)
 	if err != nil {
 		return err
 	}
-	*i.iv = &pi
-	return nil
+	return n.pos.Set(strconv.FormatBool(!b))
 }
 
-func (i *ndi64f) Get() interface{} {
-	return *i.iv
+func (n *negbf) Get() interface{} {
+	return n.pos.(flag.Getter).Get()
 }
 
-type nduif struct {
-	uiv     **uint
-	example string
+func (n *negbf) IsBoolFlag() bool {
+	return true
 }
 
-func (ui *nduif) String() string {
-	return ui.example
+// negationOf - the name of the -no- half of the named negatable bool, ""
+// if it isn't one.
+func (ndf *NDFlagSet) negationOf(name string) string {
+	fl, pos := ndf.Lookup("no-"+name), ndf.Lookup(name)
+	if fl == nil || pos == nil {
+		return ""
+	}
+	if n, ok := unwrapValue(fl.Value).(*negbf); ok && n.pos == pos.Value {
+		return fl.Name
+	}
+	return ""
 }
 
-func (ui *nduif) Set(val string) error {
-	pui, err := strconv.ParseUint(val, 10, 64)
+// countVal - the new count after an occurrence of a count flag: a bare
+// flag (which the flag package passes as "true") increments, an explicit
+// number sets the count outright, and false resets it.
+func countVal(cur int, val string) (int, error) {
+	if n, err := strconv.Atoi(val); err == nil {
+		return n, nil
+	}
+	b, err := strconv.ParseBool(val)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("invalid count %q", val)
 	}
-	pui2 := uint(pui)
-	*ui.uiv = &pui2
-	return nil
-}
-
-func (ui nduif) Get() interface{} {
-	return *ui.uiv
+	if b {
+		return cur + 1, nil
+	}
+	return 0, nil
 }
 
-type ndui64f struct {
-	uiv     **uint64
-	example string
+type ndcountf struct {
+	cv **int
 }
 
-func (ui *ndui64f) String() string {
-	return ui.example
+func (c *ndcountf) String() string {
+	return ""
 }
 
-func (ui *ndui64f) Set(val string) error {
-	pui, err := strconv.ParseUint(val, 10, 64)
+func (c *ndcountf) Set(val string) error {
+	cur := 0
+	if *c.cv != nil {
+		cur = **c.cv
+	}
+	n, err := countVal(cur, val)
 	if err != nil {
 		return err
 	}
-	*ui.uiv = &pui
+	*c.cv = &n
 	return nil
 }
 
-func (ui *ndui64f) Get() interface{} { return *ui.uiv }
+func (c *ndcountf) Get() interface{} {
+	return *c.cv
+}
 
-type ndff struct {
-	fv      **float64
-	example string
+func (c *ndcountf) repeatable() {}
+
+func (c *ndcountf) reset() {
+	*c.cv = nil
 }
 
-func (f *ndff) String() string {
-	return f.example
+func (c *ndcountf) IsBoolFlag() bool {
+	return true
 }
 
-func (f *ndff) Set(val string) error {
-	pf, err := strconv.ParseFloat(val, 64)
-	if err != nil {
-		return err
+// splitKV - splits a key=value map flag argument.
+func splitKV(val string) (string, string, error) {
+	i := strings.Index(val, "=")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid map entry %q, expected key=value", val)
 	}
-	*f.fv = &pf
-	return nil
+	return val[:i], val[i+1:], nil
 }
 
-func (f *ndff) Get() interface{} {
-	return *f.fv
+// MapOptions - controls how the map flag variants split their value.
+type MapOptions struct {
+	// ItemSep, if set, separates several key=value items in one
+	// occurrence, e.g. "," for -label=env=prod,team=core.  Empty items
+	// are skipped.
+	ItemSep string
 }
 
-type nddf struct {
-	dv      **time.Duration
-	example string
+// entries - the key, value pairs of a map flag argument.  Nothing is
+// returned if any item is malformed, so the map stays as it was.
+func (o MapOptions) entries(val string) ([][2]string, error) {
+	items := []string{val}
+	if o.ItemSep != "" {
+		items = strings.Split(val, o.ItemSep)
+	}
+	out := make([][2]string, 0, len(items))
+	for i, item := range items {
+		if item == "" && o.ItemSep != "" {
+			continue
+		}
+		k, v, err := splitKV(item)
+		if err != nil {
+			if len(items) > 1 {
+				return nil, fmt.Errorf("item %d: %v", i+1, err)
+			}
+			return nil, err
+		}
+		out = append(out, [2]string{k, v})
+	}
+	return out, nil
+}
+
+type ndsmf struct {
+	mv   **map[string]string
+	opts MapOptions
 }
 
-func (d *nddf) String() string {
-	return d.example
+func (m *ndsmf) String() string {
+	return ""
 }
 
-func (d nddf) Set(val string) error {
-	pd, err := time.ParseDuration(val)
+func (m *ndsmf) Set(val string) error {
+	kvs, err := m.opts.entries(val)
 	if err != nil {
 		return err
 	}
-	*d.dv = &pd
+	if *m.mv == nil {
+		*m.mv = &map[string]string{}
+	}
+	for _, kv := range kvs {
+		(**m.mv)[kv[0]] = kv[1]
+	}
 	return nil
 }
 
-func (d nddf) Get() interface{} {
-	return *d.dv
+func (m *ndsmf) Get() interface{} {
+	return *m.mv
 }
 
+func (m *ndsmf) repeatable() {}
+
+func (m *ndsmf) reset() {
+	*m.mv = nil
+}
+
+// funcf - the Value behind NDFunc.
+type funcf func(string) error
+
+func (f funcf) String() string {
+	return ""
+}
+
+func (f funcf) Set(val string) error {
+	return f(val)
+}
+
+func (f funcf) Get() interface{} {
+	return nil
+}
+
+func (f funcf) repeatable() {}
+
 // NDFlagSet - extends the flag package to add "no default" variants,
 // where no defaults are specified.
 type NDFlagSet struct {
 	*flag.FlagSet
-	output io.Writer
-	name   string
+	output      io.Writer
+	name        string
+	tracked     map[string]*trackedValue
+	envPrefix   string
+	envVars     map[string]string
+	required    []string
+	validators  map[string][]validator
+	aliases     map[string]string
+	deprecated  map[string]string
+	exclusive   [][]string
+	noDups      bool
+	prefixMatch bool
+	valueSep    string
+	gnuStyle    bool
+	flexInts    bool
+	stdinDash   bool
+	expandEnv   bool
+	unknown     func(name string) error
+	stdin       *stdinSrc
+	parseOnce   bool
+	parsed      bool
+	warnFunc    func(format string, args ...interface{})
+	helpName    string
+	ctx         context.Context
+	hidden      map[string]bool
+	groups      []usageGroup
+	groupOf     map[string]int
+	showHidden  bool
+	usageHeader string
+	usageFooter string
+	usageOrder  UsageOrder
+	declared    []string
 }
 
 // NewNDFlagSet - factory method, initializes the underlying FlagSet
@@ -200,6 +414,7 @@ func NewNDFlagSet(name string, errorHandling flag.ErrorHandling) *NDFlagSet {
 	ndf := &NDFlagSet{
 		FlagSet: fs,
 		name:    name,
+		tracked: make(map[string]*trackedValue),
 	}
 	ndf.FlagSet.Usage = ndf.ndfUsage
 	return ndf
@@ -217,10 +432,26 @@ func (ndf *NDFlagSet) NDString(name, example, usage string) **string {
 // NDStringVar - Similar to NDString, but you supply the double
 // string pointer.
 func (ndf *NDFlagSet) NDStringVar(sv **string, name, example, usage string) {
-	s := &ndsf{sv: sv, example: example}
+	s := &ndv[string]{v: sv, parse: parseString, example: example, quoted: true, stdin: true}
 	ndf.Var(s, name, usage)
 }
 
+// NDRune - single character flag, e.g. a delimiter like -sep=, .  Besides
+// the character itself it accepts Go style escapes like \t or \u00e9, and
+// U+00E9.  More than one character is an error.  returns double pointer,
+// if references nil the flag was not set.
+func (ndf *NDFlagSet) NDRune(name string, example rune, usage string) **rune {
+	var rv *rune
+	ndf.NDRuneVar(&rv, name, example, usage)
+	return &rv
+}
+
+// NDRuneVar - BYO pp version of NDRune
+func (ndf *NDFlagSet) NDRuneVar(rv **rune, name string, example rune, usage string) {
+	r := &ndv[rune]{v: rv, parse: parseRune, example: runeExample(example)}
+	ndf.Var(r, name, usage)
+}
+
 // NDBool - returns double bool pointer, will reference
 // nil bool pointer if flag was not set, will reference non-nil otherwise.
 // This allows you to differentiate between the zero val (false) and not set.
@@ -233,7 +464,39 @@ func (ndf *NDFlagSet) NDBool(name string, example bool, usage string) **bool {
 // NDBoolVar - similar to NDBool, but you supply the double
 // bool pointer.
 func (ndf *NDFlagSet) NDBoolVar(bv **bool, name string, example bool, usage string) {
-	b := &ndbf{bv: bv, example: strconv.FormatBool(example)}
+	b := &ndv[bool]{v: bv, parse: strconv.ParseBool, example: strconv.FormatBool(example), isBool: true}
+	ndf.Var(b, name, usage)
+}
+
+// NDBoolNegatable - NDBool that also registers -no-name, so -cache sets
+// true and -no-cache sets false.  Both share the double pointer, which
+// references nil unless one of them is given, with the last one winning
+// if both are.  Setting -no-name also counts as setting name for IsSet.
+func (ndf *NDFlagSet) NDBoolNegatable(name string, example bool, usage string) **bool {
+	var bv *bool
+	ndf.NDBoolNegatableVar(&bv, name, example, usage)
+	return &bv
+}
+
+// NDBoolNegatableVar - BYO pp version of NDBoolNegatable
+func (ndf *NDFlagSet) NDBoolNegatableVar(bv **bool, name string, example bool, usage string) {
+	ndf.NDBoolVar(bv, name, example, usage)
+	n := &negbf{pos: ndf.Lookup(name).Value, example: strconv.FormatBool(!example)}
+	ndf.Var(n, "no-"+name, "negates -"+name)
+}
+
+// NDBoolExtended - NDBool that also accepts yes/no, y/n and on/off, in
+// any case, for -enabled=yes.  The plain NDBool stays as strict as
+// strconv.ParseBool.
+func (ndf *NDFlagSet) NDBoolExtended(name string, example bool, usage string) **bool {
+	var bv *bool
+	ndf.NDBoolExtendedVar(&bv, name, example, usage)
+	return &bv
+}
+
+// NDBoolExtendedVar - BYO pp version of NDBoolExtended
+func (ndf *NDFlagSet) NDBoolExtendedVar(bv **bool, name string, example bool, usage string) {
+	b := &ndv[bool]{v: bv, parse: parseBoolExtended, example: strconv.FormatBool(example), isBool: true}
 	ndf.Var(b, name, usage)
 }
 
@@ -248,7 +511,7 @@ func (ndf *NDFlagSet) NDInt(name string, example int, usage string) **int {
 
 // NDIntVar - similar to NDInt, but you sply the double pointer.
 func (ndf *NDFlagSet) NDIntVar(iv **int, name string, example int, usage string) {
-	i := &ndif{iv: iv, example: strconv.FormatInt(int64(example), 10)}
+	i := &ndv[int]{v: iv, parse: strconv.Atoi, example: strconv.FormatInt(int64(example), 10)}
 	ndf.Var(i, name, usage)
 }
 
@@ -261,7 +524,46 @@ func (ndf *NDFlagSet) NDInt64(name string, example int64, usage string) **int64
 
 // NDInt64Var - NDIntVar but for int64
 func (ndf *NDFlagSet) NDInt64Var(iv **int64, name string, example int64, usage string) {
-	i := &ndi64f{iv: iv, example: strconv.FormatInt(example, 10)}
+	i := &ndv[int64]{v: iv, parse: parseSigned[int64](64), example: strconv.FormatInt(example, 10)}
+	ndf.Var(i, name, usage)
+}
+
+// NDInt8 - NDInt but type is int8
+func (ndf *NDFlagSet) NDInt8(name string, example int8, usage string) **int8 {
+	var iv *int8
+	ndf.NDInt8Var(&iv, name, example, usage)
+	return &iv
+}
+
+// NDInt8Var - NDIntVar but for int8
+func (ndf *NDFlagSet) NDInt8Var(iv **int8, name string, example int8, usage string) {
+	i := &ndv[int8]{v: iv, parse: parseSigned[int8](8), example: strconv.FormatInt(int64(example), 10)}
+	ndf.Var(i, name, usage)
+}
+
+// NDInt16 - NDInt but type is int16
+func (ndf *NDFlagSet) NDInt16(name string, example int16, usage string) **int16 {
+	var iv *int16
+	ndf.NDInt16Var(&iv, name, example, usage)
+	return &iv
+}
+
+// NDInt16Var - NDIntVar but for int16
+func (ndf *NDFlagSet) NDInt16Var(iv **int16, name string, example int16, usage string) {
+	i := &ndv[int16]{v: iv, parse: parseSigned[int16](16), example: strconv.FormatInt(int64(example), 10)}
+	ndf.Var(i, name, usage)
+}
+
+// NDInt32 - NDInt but type is int32
+func (ndf *NDFlagSet) NDInt32(name string, example int32, usage string) **int32 {
+	var iv *int32
+	ndf.NDInt32Var(&iv, name, example, usage)
+	return &iv
+}
+
+// NDInt32Var - NDIntVar but for int32
+func (ndf *NDFlagSet) NDInt32Var(iv **int32, name string, example int32, usage string) {
+	i := &ndv[int32]{v: iv, parse: parseSigned[int32](32), example: strconv.FormatInt(int64(example), 10)}
 	ndf.Var(i, name, usage)
 }
 
@@ -274,7 +576,7 @@ func (ndf *NDFlagSet) NDUint(name string, example uint, usage string) **uint {
 
 // NDUintVar - same as NDUint, but you supply the double p.
 func (ndf *NDFlagSet) NDUintVar(uiv **uint, name string, example uint, usage string) {
-	ui := &nduif{uiv: uiv, example: strconv.FormatUint(uint64(example), 10)}
+	ui := &ndv[uint]{v: uiv, parse: parseUnsigned[uint](64), example: strconv.FormatUint(uint64(example), 10)}
 	ndf.Var(ui, name, usage)
 }
 
@@ -287,10 +589,65 @@ func (ndf *NDFlagSet) NDUint64(name string, example uint64, usage string) **uint
 
 // NDUint64Var - uint64 version of NDUintVar
 func (ndf *NDFlagSet) NDUint64Var(uiv **uint64, name string, example uint64, usage string) {
-	ui := &ndui64f{uiv: uiv, example: strconv.FormatUint(example, 10)}
+	ui := &ndv[uint64]{v: uiv, parse: parseUnsigned[uint64](64), example: strconv.FormatUint(example, 10)}
+	ndf.Var(ui, name, usage)
+}
+
+// NDUint8 - uint8 version of NDUint
+func (ndf *NDFlagSet) NDUint8(name string, example uint8, usage string) **uint8 {
+	var uiv *uint8
+	ndf.NDUint8Var(&uiv, name, example, usage)
+	return &uiv
+}
+
+// NDUint8Var - uint8 version of NDUintVar
+func (ndf *NDFlagSet) NDUint8Var(uiv **uint8, name string, example uint8, usage string) {
+	ui := &ndv[uint8]{v: uiv, parse: parseUnsigned[uint8](8), example: strconv.FormatUint(uint64(example), 10)}
 	ndf.Var(ui, name, usage)
 }
 
+// NDUint16 - uint16 version of NDUint
+func (ndf *NDFlagSet) NDUint16(name string, example uint16, usage string) **uint16 {
+	var uiv *uint16
+	ndf.NDUint16Var(&uiv, name, example, usage)
+	return &uiv
+}
+
+// NDUint16Var - uint16 version of NDUintVar
+func (ndf *NDFlagSet) NDUint16Var(uiv **uint16, name string, example uint16, usage string) {
+	ui := &ndv[uint16]{v: uiv, parse: parseUnsigned[uint16](16), example: strconv.FormatUint(uint64(example), 10)}
+	ndf.Var(ui, name, usage)
+}
+
+// NDUint32 - uint32 version of NDUint
+func (ndf *NDFlagSet) NDUint32(name string, example uint32, usage string) **uint32 {
+	var uiv *uint32
+	ndf.NDUint32Var(&uiv, name, example, usage)
+	return &uiv
+}
+
+// NDUint32Var - uint32 version of NDUintVar
+func (ndf *NDFlagSet) NDUint32Var(uiv **uint32, name string, example uint32, usage string) {
+	ui := &ndv[uint32]{v: uiv, parse: parseUnsigned[uint32](32), example: strconv.FormatUint(uint64(example), 10)}
+	ndf.Var(ui, name, usage)
+}
+
+// NDBigInt - arbitrary precision integer flag, parsed with
+// big.Int.SetString in base 0, so 0x, 0o and 0b prefixes and _
+// separators are accepted.  returns double pointer, if references nil
+// the flag was not set.
+func (ndf *NDFlagSet) NDBigInt(name string, example *big.Int, usage string) **big.Int {
+	var bv *big.Int
+	ndf.NDBigIntVar(&bv, name, example, usage)
+	return &bv
+}
+
+// NDBigIntVar - BYO pp version of NDBigInt
+func (ndf *NDFlagSet) NDBigIntVar(bv **big.Int, name string, example *big.Int, usage string) {
+	b := &ndv[big.Int]{v: bv, parse: parseBigInt, example: bigIntExample(example)}
+	ndf.Var(b, name, usage)
+}
+
 // NDFloat64 - returns double pointer to a float64.  Works the same
 // as all the other numeric types.
 func (ndf *NDFlagSet) NDFloat64(name string, example float64, usage string) **float64 {
@@ -301,10 +658,148 @@ func (ndf *NDFlagSet) NDFloat64(name string, example float64, usage string) **fl
 
 // NDFloat64Var - you supply the pointer, but same as NDFloat64
 func (ndf *NDFlagSet) NDFloat64Var(fv **float64, name string, example float64, usage string) {
-	f := &ndff{fv: fv, example: strconv.FormatFloat(example, 'g', -1, 64)}
+	f := &ndv[float64]{v: fv, parse: parseFloat[float64](64), example: strconv.FormatFloat(example, 'g', -1, 64)}
+	ndf.Var(f, name, usage)
+}
+
+// NDFloat32 - float32 version of NDFloat64
+func (ndf *NDFlagSet) NDFloat32(name string, example float32, usage string) **float32 {
+	var fv *float32
+	ndf.NDFloat32Var(&fv, name, example, usage)
+	return &fv
+}
+
+// NDFloat32Var - float32 version of NDFloat64Var
+func (ndf *NDFlagSet) NDFloat32Var(fv **float32, name string, example float32, usage string) {
+	f := &ndv[float32]{v: fv, parse: parseFloat[float32](32), example: strconv.FormatFloat(float64(example), 'g', -1, 32)}
+	ndf.Var(f, name, usage)
+}
+
+// NDUnit - number with a unit suffix from units, which maps each suffix
+// to its factor in base units, and base is the unit of a bare number.
+// With {"ms": 0.001, "s": 1} and base "s", -timeout=500ms yields 0.5, as
+// does -timeout=0.5.  Suffixes are case sensitive, anything not in units
+// is an error.  Panics if base isn't in units.  returns double pointer,
+// if the flag was not set, the pointer will reference nil.
+func (ndf *NDFlagSet) NDUnit(name string, units map[string]float64, base string, example float64, usage string) **float64 {
+	var fv *float64
+	ndf.NDUnitVar(&fv, name, units, base, example, usage)
+	return &fv
+}
+
+// NDUnitVar - BYO pp version of NDUnit
+func (ndf *NDFlagSet) NDUnitVar(fv **float64, name string, units map[string]float64, base string, example float64, usage string) {
+	f := &ndv[float64]{v: fv, parse: parseUnit(units, base), example: unitExample(units, base, example)}
 	ndf.Var(f, name, usage)
 }
 
+// NDPercent - fraction flag that takes 0.5 and 50% alike, both yielding
+// 0.5, e.g. for sampling rates.  mode says what happens to values outside
+// of 0-100%.  returns double pointer, if the flag was not set, the
+// pointer will reference nil.
+func (ndf *NDFlagSet) NDPercent(name string, example float64, mode PercentMode, usage string) **float64 {
+	var fv *float64
+	ndf.NDPercentVar(&fv, name, example, mode, usage)
+	return &fv
+}
+
+// NDPercentVar - BYO pp version of NDPercent
+func (ndf *NDFlagSet) NDPercentVar(fv **float64, name string, example float64, mode PercentMode, usage string) {
+	f := &ndv[float64]{v: fv, parse: mode.parse, example: percentExample(example)}
+	ndf.Var(f, name, usage)
+}
+
+// NDLogLevel - log level flag, taking debug, info, warn or error in any
+// case, with warning accepted for warn.  The double pointer will
+// reference nil if not set.
+func (ndf *NDFlagSet) NDLogLevel(name string, example LogLevel, usage string) **LogLevel {
+	var lv *LogLevel
+	ndf.NDLogLevelVar(&lv, name, example, usage)
+	return &lv
+}
+
+// NDLogLevelVar - BYO pp version of NDLogLevel
+func (ndf *NDFlagSet) NDLogLevelVar(lv **LogLevel, name string, example LogLevel, usage string) {
+	l := &ndv[LogLevel]{v: lv, parse: parseLogLevel, example: example.String()}
+	ndf.Var(l, name, usage)
+}
+
+// NDColor - color flag, taking #rrggbb, #rrggbbaa, or the #rgb and #rgba
+// shorthands, in either case.  The # may be left off, handy as an
+// unquoted # starts a comment in the shell.  The double pointer will
+// reference nil if not set.
+func (ndf *NDFlagSet) NDColor(name string, example color.RGBA, usage string) **color.RGBA {
+	var cv *color.RGBA
+	ndf.NDColorVar(&cv, name, example, usage)
+	return &cv
+}
+
+// NDColorVar - BYO pp version of NDColor
+func (ndf *NDFlagSet) NDColorVar(cv **color.RGBA, name string, example color.RGBA, usage string) {
+	c := &ndv[color.RGBA]{v: cv, parse: parseRGBA, example: colorExample(example)}
+	ndf.Var(c, name, usage)
+}
+
+// NDComplex128 - complex number flag, parsed with strconv.ParseComplex,
+// so -z=1+2i, -z=3 and -z=2i all work.  returns double pointer, if
+// references nil the flag was not set.
+func (ndf *NDFlagSet) NDComplex128(name string, example complex128, usage string) **complex128 {
+	var cv *complex128
+	ndf.NDComplex128Var(&cv, name, example, usage)
+	return &cv
+}
+
+// NDComplex128Var - BYO pp version of NDComplex128
+func (ndf *NDFlagSet) NDComplex128Var(cv **complex128, name string, example complex128, usage string) {
+	c := &ndv[complex128]{v: cv, parse: parseComplex, example: strconv.FormatComplex(example, 'g', -1, 128)}
+	ndf.Var(c, name, usage)
+}
+
+// NDBytes - byte size flag, accepts plain byte counts as well as sizes
+// like 10KB (decimal, 1000) or 2MiB (binary, 1024), the suffix is case
+// insensitive.  Negative sizes are an error.  returns double pointer, if
+// references nil the flag was not set.
+func (ndf *NDFlagSet) NDBytes(name string, example int64, usage string) **int64 {
+	var bv *int64
+	ndf.NDBytesVar(&bv, name, example, usage)
+	return &bv
+}
+
+// NDBytesVar - BYO pp version of NDBytes
+func (ndf *NDFlagSet) NDBytesVar(bv **int64, name string, example int64, usage string) {
+	b := &ndv[int64]{v: bv, parse: parseBytes, example: strconv.FormatInt(example, 10)}
+	ndf.Var(b, name, usage)
+}
+
+// NDBytesHex - binary flag given hex encoded, e.g. for keys or nonces,
+// decoded with hex.DecodeString.  The example is shown encoded.  returns
+// double pointer, if references nil the flag was not set.
+func (ndf *NDFlagSet) NDBytesHex(name string, example []byte, usage string) **[]byte {
+	var bv *[]byte
+	ndf.NDBytesHexVar(&bv, name, example, usage)
+	return &bv
+}
+
+// NDBytesHexVar - BYO pp version of NDBytesHex
+func (ndf *NDFlagSet) NDBytesHexVar(bv **[]byte, name string, example []byte, usage string) {
+	b := &ndv[[]byte]{v: bv, parse: hex.DecodeString, example: hex.EncodeToString(example), stdin: true}
+	ndf.Var(b, name, usage)
+}
+
+// NDBytesBase64 - same as NDBytesHex, but given in standard, padded
+// base64.
+func (ndf *NDFlagSet) NDBytesBase64(name string, example []byte, usage string) **[]byte {
+	var bv *[]byte
+	ndf.NDBytesBase64Var(&bv, name, example, usage)
+	return &bv
+}
+
+// NDBytesBase64Var - BYO pp version of NDBytesBase64
+func (ndf *NDFlagSet) NDBytesBase64Var(bv **[]byte, name string, example []byte, usage string) {
+	b := &ndv[[]byte]{v: bv, parse: base64.StdEncoding.DecodeString, example: base64.StdEncoding.EncodeToString(example), stdin: true}
+	ndf.Var(b, name, usage)
+}
+
 // NDDuration - duration flag.  returns double pointer, if references
 // nil the flag was not set, otherwise it was set.
 func (ndf *NDFlagSet) NDDuration(name string, example time.Duration, usage string) **time.Duration {
@@ -315,39 +810,629 @@ func (ndf *NDFlagSet) NDDuration(name string, example time.Duration, usage strin
 
 // NDDurationVar - BYO duration pp version of NDDuration
 func (ndf *NDFlagSet) NDDurationVar(dv **time.Duration, name string, example time.Duration, usage string) {
-	d := &nddf{dv: dv, example: example.String()}
+	d := &ndv[time.Duration]{v: dv, parse: time.ParseDuration, example: example.String()}
 	ndf.Var(d, name, usage)
 }
 
-// Lifted from / adapted from std lib flag.PrintDefauls.
+// NDTime - time flag, parsed as time.RFC3339.  returns double pointer,
+// if references nil the flag was not set, otherwise it was set.
+func (ndf *NDFlagSet) NDTime(name string, example time.Time, usage string) **time.Time {
+	return ndf.NDTimeLayout(name, time.RFC3339, example, usage)
+}
+
+// NDTimeVar - BYO time pp version of NDTime
+func (ndf *NDFlagSet) NDTimeVar(tv **time.Time, name string, example time.Time, usage string) {
+	ndf.NDTimeLayoutVar(tv, name, time.RFC3339, example, usage)
+}
+
+// NDTimeLayout - same as NDTime, but parses with the supplied layout
+// (see time.Parse).  The example is formatted with the same layout.
+func (ndf *NDFlagSet) NDTimeLayout(name, layout string, example time.Time, usage string) **time.Time {
+	var tv *time.Time
+	ndf.NDTimeLayoutVar(&tv, name, layout, example, usage)
+	return &tv
+}
+
+// NDTimeLayoutVar - BYO time pp version of NDTimeLayout
+func (ndf *NDFlagSet) NDTimeLayoutVar(tv **time.Time, name, layout string, example time.Time, usage string) {
+	t := &ndv[time.Time]{v: tv, parse: parseTime(layout), example: example.Format(layout)}
+	ndf.Var(t, name, usage)
+}
+
+// NDUnixTime - time flag given as seconds since the epoch, e.g. 1600000000
+// or 1600000000.25, negative values are before 1970.  The time is in the
+// local zone, as with time.Unix.  The example is shown as the epoch
+// seconds.  returns double pointer, if references nil the flag was not set.
+func (ndf *NDFlagSet) NDUnixTime(name string, example time.Time, usage string) **time.Time {
+	var tv *time.Time
+	ndf.NDUnixTimeVar(&tv, name, example, usage)
+	return &tv
+}
+
+// NDUnixTimeVar - BYO pp version of NDUnixTime
+func (ndf *NDFlagSet) NDUnixTimeVar(tv **time.Time, name string, example time.Time, usage string) {
+	t := &ndv[time.Time]{v: tv, parse: parseUnixTime, example: unixExample(example)}
+	ndf.Var(t, name, usage)
+}
+
+// NDIP - IP address flag, parsed with net.ParseIP.  returns double
+// pointer, if references nil the flag was not set, otherwise it was set.
+func (ndf *NDFlagSet) NDIP(name string, example net.IP, usage string) **net.IP {
+	var ipv *net.IP
+	ndf.NDIPVar(&ipv, name, example, usage)
+	return &ipv
+}
+
+// NDIPVar - BYO IP pp version of NDIP
+func (ndf *NDFlagSet) NDIPVar(ipv **net.IP, name string, example net.IP, usage string) {
+	ip := &ndv[net.IP]{v: ipv, parse: parseIP, example: example.String()}
+	ndf.Var(ip, name, usage)
+}
+
+// NDIPNet - CIDR flag, parsed with net.ParseCIDR.  The host part of the
+// address is discarded, so -allow=10.1.2.3/8 yields 10.0.0.0/8.  returns
+// double pointer, if references nil the flag was not set.
+func (ndf *NDFlagSet) NDIPNet(name string, example net.IPNet, usage string) **net.IPNet {
+	var nv *net.IPNet
+	ndf.NDIPNetVar(&nv, name, example, usage)
+	return &nv
+}
+
+// NDIPNetVar - BYO IPNet pp version of NDIPNet
+func (ndf *NDFlagSet) NDIPNetVar(nv **net.IPNet, name string, example net.IPNet, usage string) {
+	n := &ndv[net.IPNet]{v: nv, parse: parseIPNet(false), example: example.String()}
+	ndf.Var(n, name, usage)
+}
+
+// NDIPNetHost - same as NDIPNet, but the IP keeps the host part as
+// given, so -addr=10.1.2.3/8 yields 10.1.2.3/8.
+func (ndf *NDFlagSet) NDIPNetHost(name string, example net.IPNet, usage string) **net.IPNet {
+	var nv *net.IPNet
+	ndf.NDIPNetHostVar(&nv, name, example, usage)
+	return &nv
+}
+
+// NDIPNetHostVar - BYO IPNet pp version of NDIPNetHost
+func (ndf *NDFlagSet) NDIPNetHostVar(nv **net.IPNet, name string, example net.IPNet, usage string) {
+	n := &ndv[net.IPNet]{v: nv, parse: parseIPNet(true), example: example.String()}
+	ndf.Var(n, name, usage)
+}
+
+// NDAddr - IP address flag backed by net/netip, parsed with
+// netip.ParseAddr, so zones like fe80::1%eth0 are accepted.  returns
+// double pointer, if references nil the flag was not set.
+func (ndf *NDFlagSet) NDAddr(name string, example netip.Addr, usage string) **netip.Addr {
+	var av *netip.Addr
+	ndf.NDAddrVar(&av, name, example, usage)
+	return &av
+}
+
+// NDAddrVar - BYO Addr pp version of NDAddr
+func (ndf *NDFlagSet) NDAddrVar(av **netip.Addr, name string, example netip.Addr, usage string) {
+	a := &ndv[netip.Addr]{v: av, parse: netip.ParseAddr, example: netipExample(example)}
+	ndf.Var(a, name, usage)
+}
+
+// NDPrefix - CIDR flag backed by net/netip, parsed with
+// netip.ParsePrefix.  As with netip, the address keeps its host bits, use
+// Masked() if you want the network.  returns double pointer, if references
+// nil the flag was not set.
+func (ndf *NDFlagSet) NDPrefix(name string, example netip.Prefix, usage string) **netip.Prefix {
+	var pv *netip.Prefix
+	ndf.NDPrefixVar(&pv, name, example, usage)
+	return &pv
+}
+
+// NDPrefixVar - BYO Prefix pp version of NDPrefix
+func (ndf *NDFlagSet) NDPrefixVar(pv **netip.Prefix, name string, example netip.Prefix, usage string) {
+	p := &ndv[netip.Prefix]{v: pv, parse: netip.ParsePrefix, example: netipExample(example)}
+	ndf.Var(p, name, usage)
+}
+
+// NDAddrPort - host:port flag backed by net/netip, parsed with
+// netip.ParseAddrPort, so -listen=127.0.0.1:8080 and -listen=[::1]:9090
+// both work.  The host must be an IP and the port is required.  returns
+// double pointer, if references nil the flag was not set.
+func (ndf *NDFlagSet) NDAddrPort(name string, example netip.AddrPort, usage string) **netip.AddrPort {
+	var av *netip.AddrPort
+	ndf.NDAddrPortVar(&av, name, example, usage)
+	return &av
+}
+
+// NDAddrPortVar - BYO AddrPort pp version of NDAddrPort
+func (ndf *NDFlagSet) NDAddrPortVar(av **netip.AddrPort, name string, example netip.AddrPort, usage string) {
+	a := &ndv[netip.AddrPort]{v: av, parse: netip.ParseAddrPort, example: netipExample(example)}
+	ndf.Var(a, name, usage)
+}
+
+// NDURL - url flag, parsed with url.Parse.  Relative urls are accepted.
+// returns double pointer, if references nil the flag was not set.
+func (ndf *NDFlagSet) NDURL(name string, example *url.URL, usage string) **url.URL {
+	var uv *url.URL
+	ndf.NDURLVar(&uv, name, example, usage)
+	return &uv
+}
+
+// NDURLVar - BYO url pp version of NDURL
+func (ndf *NDFlagSet) NDURLVar(uv **url.URL, name string, example *url.URL, usage string) {
+	u := &ndv[url.URL]{v: uv, parse: parseURL(false), example: urlExample(example)}
+	ndf.Var(u, name, usage)
+}
+
+// NDAbsURL - same as NDURL, but the url must have a scheme.
+func (ndf *NDFlagSet) NDAbsURL(name string, example *url.URL, usage string) **url.URL {
+	var uv *url.URL
+	ndf.NDAbsURLVar(&uv, name, example, usage)
+	return &uv
+}
+
+// NDAbsURLVar - BYO url pp version of NDAbsURL
+func (ndf *NDFlagSet) NDAbsURLVar(uv **url.URL, name string, example *url.URL, usage string) {
+	u := &ndv[url.URL]{v: uv, parse: parseURL(true), example: urlExample(example)}
+	ndf.Var(u, name, usage)
+}
+
+// NDRegexp - regular expression flag, compiled with regexp.Compile.  The
+// example is the pattern.  returns double pointer, if references nil the
+// flag was not set, so no filter can be told apart from a match anything
+// pattern.
+func (ndf *NDFlagSet) NDRegexp(name, example, usage string) **regexp.Regexp {
+	var rv *regexp.Regexp
+	ndf.NDRegexpVar(&rv, name, example, usage)
+	return &rv
+}
+
+// NDRegexpVar - BYO pp version of NDRegexp
+func (ndf *NDFlagSet) NDRegexpVar(rv **regexp.Regexp, name, example, usage string) {
+	r := &ndv[regexp.Regexp]{v: rv, parse: parseRegexp, example: example, quoted: true}
+	ndf.Var(r, name, usage)
+}
+
+// NDStringSlice - repeatable string flag.  Each occurrence appends to the
+// slice, so -tag=a -tag=b yields ["a", "b"].  The double pointer will
+// reference nil until the flag appears at least once.
+func (ndf *NDFlagSet) NDStringSlice(name, usage string) **[]string {
+	var sv *[]string
+	ndf.NDStringSliceVar(&sv, name, usage)
+	return &sv
+}
+
+// NDStringSliceVar - BYO pp version of NDStringSlice
+func (ndf *NDFlagSet) NDStringSliceVar(sv **[]string, name, usage string) {
+	s := &ndsv[string]{v: sv, parse: parseString}
+	ndf.Var(s, name, usage)
+}
+
+// NDStringSliceN - NDStringSlice that must collect between min and max
+// values, e.g. exactly 2 for a pair of coordinates, or at most 3 tags.
+// A negative max means no upper bound.  The count is checked along with
+// the validators, so Parse and Validate return an error naming both the
+// count and the expected range.  Like the validators it's only checked
+// once the flag is set: left out, the flag passes even with a min above
+// 0, as for any optional flag, so use Require and Validate to demand it.
+func (ndf *NDFlagSet) NDStringSliceN(name string, min, max int, usage string) **[]string {
+	var sv *[]string
+	ndf.NDStringSliceNVar(&sv, name, min, max, usage)
+	return &sv
+}
+
+// NDStringSliceNVar - BYO pp version of NDStringSliceN
+func (ndf *NDFlagSet) NDStringSliceNVar(sv **[]string, name string, min, max int, usage string) {
+	ndf.NDStringSliceVar(sv, name, usage)
+	// counts what it's handed rather than *sv, so it holds on a Clone
+	ndf.addValidator(name, func(_ context.Context, v interface{}) error {
+		s, _ := v.(*[]string)
+		if s == nil {
+			return checkCount(0, min, max)
+		}
+		return checkCount(len(*s), min, max)
+	})
+}
+
+// NDIntSlice - repeatable int flag, -id=1 -id=2 yields [1, 2].  Parsing
+// stops at the first bad value, leaving what was collected before it.
+// The double pointer will reference nil until the flag appears.
+func (ndf *NDFlagSet) NDIntSlice(name, usage string) **[]int {
+	var iv *[]int
+	ndf.NDIntSliceVar(&iv, name, usage)
+	return &iv
+}
+
+// NDIntSliceVar - BYO pp version of NDIntSlice
+func (ndf *NDFlagSet) NDIntSliceVar(iv **[]int, name, usage string) {
+	i := &ndsv[int]{v: iv, parse: strconv.Atoi}
+	ndf.Var(i, name, usage)
+}
+
+// NDInt32Slice - repeatable int32 flag, e.g. for protobuf repeated int32
+// fields.  A value out of int32 range is an error, and as with
+// NDIntSlice parsing stops at the first bad value.  The double pointer
+// will reference nil until the flag appears.
+func (ndf *NDFlagSet) NDInt32Slice(name, usage string) **[]int32 {
+	var iv *[]int32
+	ndf.NDInt32SliceVar(&iv, name, usage)
+	return &iv
+}
+
+// NDInt32SliceVar - BYO pp version of NDInt32Slice
+func (ndf *NDFlagSet) NDInt32SliceVar(iv **[]int32, name, usage string) {
+	i := &ndsv[int32]{v: iv, parse: parseSigned[int32](32)}
+	ndf.Var(i, name, usage)
+}
+
+// NDInt16Slice - int16 version of NDInt32Slice
+func (ndf *NDFlagSet) NDInt16Slice(name, usage string) **[]int16 {
+	var iv *[]int16
+	ndf.NDInt16SliceVar(&iv, name, usage)
+	return &iv
+}
+
+// NDInt16SliceVar - BYO pp version of NDInt16Slice
+func (ndf *NDFlagSet) NDInt16SliceVar(iv **[]int16, name, usage string) {
+	i := &ndsv[int16]{v: iv, parse: parseSigned[int16](16)}
+	ndf.Var(i, name, usage)
+}
+
+// NDFloat64Slice - repeatable float64 flag, -w=0.1 -w=0.2 yields
+// [0.1, 0.2].  NaN and infinities are rejected, as they're rarely what
+// was meant in a list of numbers.  Parsing stops at the first bad value.
+// The double pointer will reference nil until the flag appears.
+func (ndf *NDFlagSet) NDFloat64Slice(name, usage string) **[]float64 {
+	var fv *[]float64
+	ndf.NDFloat64SliceVar(&fv, name, usage)
+	return &fv
+}
+
+// NDFloat64SliceVar - BYO pp version of NDFloat64Slice
+func (ndf *NDFlagSet) NDFloat64SliceVar(fv **[]float64, name, usage string) {
+	f := &ndsv[float64]{v: fv, parse: parseFiniteFloat}
+	ndf.Var(f, name, usage)
+}
+
+// NDDurationSlice - repeatable duration flag, -retry=1s -retry=5s yields
+// [1s, 5s], in the order given.  Parsing stops at the first bad value.
+// The double pointer will reference nil until the flag appears.
+func (ndf *NDFlagSet) NDDurationSlice(name, usage string) **[]time.Duration {
+	var dv *[]time.Duration
+	ndf.NDDurationSliceVar(&dv, name, usage)
+	return &dv
+}
+
+// NDDurationSliceVar - BYO pp version of NDDurationSlice
+func (ndf *NDFlagSet) NDDurationSliceVar(dv **[]time.Duration, name, usage string) {
+	d := &ndsv[time.Duration]{v: dv, parse: time.ParseDuration}
+	ndf.Var(d, name, usage)
+}
+
+// NDStringMap - repeatable key=value flag, -label=env=prod -label=team=core
+// yields {"env": "prod", "team": "core"}.  Later occurrences of a key
+// overwrite earlier ones.  The double pointer will reference nil until the
+// flag appears.
+func (ndf *NDFlagSet) NDStringMap(name, usage string) **map[string]string {
+	var mv *map[string]string
+	ndf.NDStringMapVar(&mv, name, usage)
+	return &mv
+}
+
+// NDStringMapVar - BYO pp version of NDStringMap
+func (ndf *NDFlagSet) NDStringMapVar(mv **map[string]string, name, usage string) {
+	ndf.NDStringMapOptsVar(mv, name, MapOptions{}, usage)
+}
+
+// NDStringMapOpts - NDStringMap with several items per occurrence.
+func (ndf *NDFlagSet) NDStringMapOpts(name string, opts MapOptions, usage string) **map[string]string {
+	var mv *map[string]string
+	ndf.NDStringMapOptsVar(&mv, name, opts, usage)
+	return &mv
+}
+
+// NDStringMapOptsVar - BYO pp version of NDStringMapOpts
+func (ndf *NDFlagSet) NDStringMapOptsVar(mv **map[string]string, name string, opts MapOptions, usage string) {
+	m := &ndsmf{mv: mv, opts: opts}
+	ndf.Var(m, name, usage)
+}
+
+// NDCSVString - comma separated string flag, -hosts=a,b,c yields
+// ["a", "b", "c"].  Whitespace around each field is trimmed and empty
+// fields are dropped.  The double pointer will reference nil if not set.
+func (ndf *NDFlagSet) NDCSVString(name string, example []string, usage string) **[]string {
+	return ndf.NDCSVStringOpts(name, example, CSVOptions{}, usage)
+}
+
+// NDCSVStringVar - BYO pp version of NDCSVString
+func (ndf *NDFlagSet) NDCSVStringVar(sv **[]string, name string, example []string, usage string) {
+	ndf.NDCSVStringOptsVar(sv, name, example, CSVOptions{}, usage)
+}
+
+// NDCSVStringOpts - NDCSVString with a custom separator / empty handling.
+func (ndf *NDFlagSet) NDCSVStringOpts(name string, example []string, opts CSVOptions, usage string) **[]string {
+	var sv *[]string
+	ndf.NDCSVStringOptsVar(&sv, name, example, opts, usage)
+	return &sv
+}
+
+// NDCSVStringOptsVar - BYO pp version of NDCSVStringOpts
+func (ndf *NDFlagSet) NDCSVStringOptsVar(sv **[]string, name string, example []string, opts CSVOptions, usage string) {
+	s := &ndv[[]string]{v: sv, parse: opts.parse, example: strings.Join(example, opts.sep())}
+	ndf.Var(s, name, usage)
+}
+
+// NDPath - string flag holding a file system path, checked according to
+// opts.  The double pointer will reference nil if not set.
+func (ndf *NDFlagSet) NDPath(name, example string, opts PathOptions, usage string) **string {
+	var sv *string
+	ndf.NDPathVar(&sv, name, example, opts, usage)
+	return &sv
+}
+
+// NDPathVar - BYO pp version of NDPath
+func (ndf *NDFlagSet) NDPathVar(sv **string, name, example string, opts PathOptions, usage string) {
+	s := &ndv[string]{v: sv, parse: opts.parse, example: example, quoted: true}
+	ndf.Var(s, name, usage)
+}
+
+// NDEnum - string flag that only accepts one of choices, matched case
+// sensitively.  The double pointer will reference nil if not set.
+func (ndf *NDFlagSet) NDEnum(name string, choices []string, usage string) **string {
+	return ndf.NDEnumOpts(name, choices, EnumOptions{}, usage)
+}
+
+// NDEnumVar - BYO pp version of NDEnum
+func (ndf *NDFlagSet) NDEnumVar(sv **string, name string, choices []string, usage string) {
+	ndf.NDEnumOptsVar(sv, name, choices, EnumOptions{}, usage)
+}
+
+// NDEnumOpts - NDEnum with matching options.
+func (ndf *NDFlagSet) NDEnumOpts(name string, choices []string, opts EnumOptions, usage string) **string {
+	var sv *string
+	ndf.NDEnumOptsVar(&sv, name, choices, opts, usage)
+	return &sv
+}
+
+// NDEnumOptsVar - BYO pp version of NDEnumOpts
+func (ndf *NDFlagSet) NDEnumOptsVar(sv **string, name string, choices []string, opts EnumOptions, usage string) {
+	e := &ndenumf{
+		ndv:     &ndv[string]{v: sv, parse: opts.parser(choices)},
+		choices: choices,
+	}
+	ndf.Var(e, name, usage)
+}
+
+// NDCount - counting flag, each occurrence of -v increments it so
+// -v -v -v yields 3.  -v=2 sets the count directly.  The double pointer
+// references nil if the flag never appears.
+func (ndf *NDFlagSet) NDCount(name, usage string) **int {
+	var cv *int
+	ndf.NDCountVar(&cv, name, usage)
+	return &cv
+}
+
+// NDCountVar - BYO pp version of NDCount
+func (ndf *NDFlagSet) NDCountVar(cv **int, name, usage string) {
+	c := &ndcountf{cv: cv}
+	ndf.Var(c, name, usage)
+}
+
+// NDFunc - same as flag.FlagSet.Func, set is called with the argument
+// of each occurrence of the flag, and an error it returns fails the
+// parse.  Storing the value is up to set, the flag set only tracks that
+// the flag was given, so IsSet works as for the other flags.
+func (ndf *NDFlagSet) NDFunc(name, usage string, set func(string) error) {
+	ndf.Var(funcf(set), name, usage)
+}
+
+// choiceLister - implemented by flags restricted to a set of choices,
+// which are listed in the usage instead of an example.
+type choiceLister interface {
+	choiceList() []string
+}
+
+// quoter - implemented by the Value types, reports whether the example is
+// a string that should be quoted in the usage.
+type quoter interface {
+	quoteExample() bool
+}
+
+// repeater - marks the Value types meant to be given more than once,
+// which SetNoDuplicates lets through.
+type repeater interface {
+	repeatable()
+}
+
+// defaulter - implemented by the scalar Value types, reports whether the
+// flag was defined with a real default by one of the ND*Default or
+// ZV*Default methods.
+type defaulter interface {
+	hasDefault() bool
+}
+
+// zeroValuer - marks the ZV family of Value types, so the usage can tell
+// them apart from the ND ones.
+type zeroValuer interface {
+	zeroValue()
+}
+
+// starter - implemented by the ZV scalar Value types, the value the
+// target holds when the flag is defined, see startValue.
+type starter interface {
+	startValue() (string, bool)
+}
+
+// printDefaults - the flag lines of the usage, under their group headings
+// if there are any groups.
 func (ndf *NDFlagSet) printDefaults() {
-	ndf.VisitAll(func(fl *flag.Flag) {
-		s := fmt.Sprintf("  -%s", fl.Name) // Two spaces before -; see next two comments.
-		name, usage := flag.UnquoteUsage(fl)
-		if len(name) > 0 {
-			s += " " + name
+	if len(ndf.groups) == 0 {
+		ndf.visitUsage(ndf.printFlag)
+		return
+	}
+	for i, g := range ndf.groups {
+		fmt.Fprintf(ndf.out(), "\n%s:\n", g.title)
+		for _, name := range g.names {
+			if ndf.groupOf[name] == i && !ndf.isHidden(name) {
+				ndf.printFlag(ndf.Lookup(name))
+			}
 		}
-		// Boolean flags of one ASCII letter are so common we
-		// treat them specially, putting their usage on the same line.
-		if len(s) <= 4 { // space, space, '-', 'x'.
-			s += "\t"
-		} else {
-			// Four spaces before the tab triggers good alignment
-			// for both 4- and 8-space tab stops.
-			s += "\n    \t"
+	}
+	header := false
+	ndf.visitUsage(func(fl *flag.Flag) {
+		if _, ok := ndf.groupOf[fl.Name]; ok {
+			return
 		}
+		if !header {
+			fmt.Fprint(ndf.out(), "\nOther flags:\n")
+			header = true
+		}
+		ndf.printFlag(fl)
+	})
+}
 
-		s += usage
+// Lifted from / adapted from std lib flag.PrintDefauls.
+func (ndf *NDFlagSet) printFlag(fl *flag.Flag) {
+	s := fmt.Sprintf("  -%s", fl.Name) // Two spaces before -; see next two comments.
+	for _, a := range ndf.aliasesOf(fl.Name) {
+		s += ", -" + a
+	}
+	name, usage := flag.UnquoteUsage(fl)
+	if len(name) > 0 {
+		s += " " + name
+	}
+	// Boolean flags of one ASCII letter are so common we
+	// treat them specially, putting their usage on the same line.
+	if len(s) <= 4 { // space, space, '-', 'x'.
+		s += "\t"
+	} else {
+		// Four spaces before the tab triggers good alignment
+		// for both 4- and 8-space tab stops.
+		s += "\n    \t"
+	}
 
-		if _, ok := fl.Value.(*ndsf); ok {
-			// put quotes on the value
-			s += fmt.Sprintf(" (example %q)", fl.DefValue)
-		} else {
-			s += fmt.Sprintf(" (example %v)", fl.DefValue)
-		}
+	s += usage
 
-		fmt.Fprint(ndf.out(), s, "\n")
-	})
+	v := unwrapValue(fl.Value)
+	label, value, show := ndf.usageValue(fl)
+	if c, ok := v.(choiceLister); ok {
+		s += fmt.Sprintf(" (one of %s)", strings.Join(c.choiceList(), "|"))
+	} else if q, ok := v.(quoter); ok && q.quoteExample() && show {
+		// put quotes on the value
+		s += fmt.Sprintf(" (%s %q)", label, value)
+	} else if value != "" {
+		s += fmt.Sprintf(" (%s %v)", label, value)
+	}
+
+	fmt.Fprint(ndf.out(), s, "\n")
+}
+
+// usageValue - what the usage shows for fl.  ND flags show their example,
+// unless defined with one of the ND*Default methods.  ZV flags show the
+// value they start out with as the default: the zero value for the plain
+// ZV methods, whatever the example, the default for the ZV*Default
+// methods, and the target's value for the Var methods.  show is false if
+// there's nothing to show.
+func (ndf *NDFlagSet) usageValue(fl *flag.Flag) (label, value string, show bool) {
+	v := unwrapValue(fl.Value)
+	if d, ok := v.(defaulter); ok && d.hasDefault() {
+		return "default", fl.DefValue, true
+	}
+	if _, ok := v.(zeroValuer); !ok {
+		return "example", fl.DefValue, true
+	}
+	if t, ok := fl.Value.(*trackedValue); ok && t.hasStart {
+		return "default", t.start, t.startOK
+	}
+	return "default", fl.DefValue, true
+}
+
+// Parse - same as flag.FlagSet.Parse, but once the arguments are parsed
+// any validators are run as well.
+func (ndf *NDFlagSet) Parse(arguments []string) error {
+	if err := ndf.parseArgs(arguments); err != nil {
+		return err
+	}
+	return ndf.postParse()
+}
+
+// ParseContext - Parse, with ctx handed to validators added with
+// AddContextValidator, and available from Context while parsing, e.g. to
+// transforms.  If ctx is already done, nothing is parsed and ctx.Err() is
+// returned as is.
+func (ndf *NDFlagSet) ParseContext(ctx context.Context, arguments []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ndf.ctx = ctx
+	defer func() { ndf.ctx = nil }()
+	return ndf.Parse(arguments)
+}
+
+// Context - the context of the ParseContext call in progress, or
+// context.Background outside of one.
+func (ndf *NDFlagSet) Context() context.Context {
+	if ndf.ctx == nil {
+		return context.Background()
+	}
+	return ndf.ctx
+}
+
+// parseArgs - flag.FlagSet.Parse, after rewriting the arguments as the
+// flag set is configured to, see normalizeArgs.
+func (ndf *NDFlagSet) parseArgs(arguments []string) error {
+	if ndf.parseOnce && ndf.parsed {
+		return ndf.fail(ErrParsedTwice)
+	}
+	ndf.parsed = true
+	arguments, err := ndf.normalizeArgs(arguments)
+	if err != nil {
+		return ndf.fail(err)
+	}
+	if ndf.helpRequested(arguments) {
+		return ndf.help()
+	}
+	return ndf.FlagSet.Parse(arguments)
+}
+
+// ErrParsedTwice - returned by the Parse methods for a second parse
+// without a Reset in between, once EnsureParsedOnce was called.
+var ErrParsedTwice = errors.New("flag set already parsed, Reset it first")
+
+// EnsureParsedOnce - makes a second Parse, by any of the Parse methods,
+// fail with ErrParsedTwice unless Reset was called in between.  Parsing
+// again on top of earlier values mixes the two, e.g. slices keep
+// growing and IsSet reports flags only the first parse saw, which is
+// rarely what was meant outside of tests.
+func (ndf *NDFlagSet) EnsureParsedOnce() {
+	ndf.parseOnce = true
+}
+
+// Parsed - reports whether the flag set was parsed since it was created
+// or last Reset.
+func (ndf *NDFlagSet) Parsed() bool {
+	return ndf.parsed
+}
+
+// Rest - the arguments left after the flags, same as Args.  As with the
+// flag package, parsing stops at the first non-flag argument or at "--",
+// which is dropped, so everything after that is in Rest even if it looks
+// like a flag.
+func (ndf *NDFlagSet) Rest() []string {
+	return ndf.Args()
+}
+
+// postParse - the checks we run once all values are in place.
+func (ndf *NDFlagSet) postParse() error {
+	if err := ndf.applyEnvVars(); err != nil {
+		return ndf.fail(err)
+	}
+	ndf.warnDeprecated()
+	if err := ndf.runValidators(); err != nil {
+		return ndf.fail(err)
+	}
+	if err := ndf.checkExclusive(); err != nil {
+		return ndf.fail(err)
+	}
+	if err := ndf.checkDuplicates(); err != nil {
+		return ndf.fail(err)
+	}
+	return nil
 }
 
 // SetOutput sets the destination for usage and error messages.
@@ -364,12 +1449,157 @@ func (ndf *NDFlagSet) out() io.Writer {
 	return ndf.output
 }
 
+// SetWarnFunc - routes the non-fatal diagnostics, like deprecation
+// warnings, through fn, e.g. to your logger, rather than writing them to
+// the output.  fn gets a format and args as for fmt.Printf, without a
+// trailing newline.  nil puts back the default.
+func (ndf *NDFlagSet) SetWarnFunc(fn func(format string, args ...interface{})) {
+	ndf.warnFunc = fn
+}
+
+// warnf - reports a non-fatal problem, see SetWarnFunc.
+func (ndf *NDFlagSet) warnf(format string, args ...interface{}) {
+	if ndf.warnFunc != nil {
+		ndf.warnFunc(format, args...)
+		return
+	}
+	fmt.Fprintf(ndf.out(), format+"\n", args...)
+}
+
+// fail - reports an error found outside of the flag package's own
+// parsing (env, validation, etc), honoring the ErrorHandling the flag set
+// was created with.
+func (ndf *NDFlagSet) fail(err error) error {
+	fmt.Fprintln(ndf.out(), err)
+	ndf.FlagSet.Usage()
+	switch ndf.ErrorHandling() {
+	case flag.ExitOnError:
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// UsageOrder - the order flags are listed in the usage.
+type UsageOrder int
+
+const (
+	// OrderLexical lists flags sorted by name, as the flag package does.
+	OrderLexical UsageOrder = iota
+	// OrderDeclared lists flags in the order they were defined.
+	OrderDeclared
+)
+
+// SetUsageOrder - sets the order flags are listed in the usage,
+// OrderLexical by default.  With OrderDeclared, flags defined directly on
+// the embedded FlagSet, which the flag set doesn't see being defined, are
+// listed last, sorted by name.
+func (ndf *NDFlagSet) SetUsageOrder(mode UsageOrder) {
+	ndf.usageOrder = mode
+}
+
+// usageGroup - a titled section of the usage, see Group.
+type usageGroup struct {
+	title string
+	names []string
+}
+
+// Group - lists the named flags in the usage under a section headed
+// title, in the order given, rather than in the one flat list.  Once
+// there are groups, the flags in none of them follow under "Other
+// flags".  The sections come in the order first grouped, and grouping
+// under a title again adds to that section.  A flag is only in one
+// group, the last it was put in.  The -no- half of a negatable bool goes
+// with its flag, aliases are listed with theirs.
+func (ndf *NDFlagSet) Group(title string, names ...string) {
+	i := 0
+	for i < len(ndf.groups) && ndf.groups[i].title != title {
+		i++
+	}
+	if i == len(ndf.groups) {
+		ndf.groups = append(ndf.groups, usageGroup{title: title})
+	}
+	if ndf.groupOf == nil {
+		ndf.groupOf = make(map[string]int)
+	}
+	add := func(name string) {
+		if j, ok := ndf.groupOf[name]; ok && j == i {
+			return
+		}
+		ndf.groups[i].names = append(ndf.groups[i].names, name)
+		ndf.groupOf[name] = i
+	}
+	for _, name := range names {
+		if c, ok := ndf.aliases[name]; ok {
+			name = c
+		}
+		if ndf.Lookup(name) == nil {
+			continue
+		}
+		add(name)
+		if neg := ndf.negationOf(name); neg != "" {
+			add(neg)
+		}
+	}
+}
+
+// visitUsage - visitCanonical, in the usage order, skipping hidden flags.
+func (ndf *NDFlagSet) visitUsage(visit func(*flag.Flag)) {
+	fn := func(fl *flag.Flag) {
+		if !ndf.isHidden(fl.Name) {
+			visit(fl)
+		}
+	}
+	if ndf.usageOrder != OrderDeclared {
+		ndf.visitCanonical(fn)
+		return
+	}
+	seen := make(map[string]bool)
+	for _, name := range ndf.declared {
+		if fl := ndf.Lookup(name); fl != nil && !seen[name] {
+			seen[name] = true
+			fn(fl)
+		}
+	}
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		if !seen[fl.Name] {
+			fn(fl)
+		}
+	})
+}
+
+// SetUsageHeader - replaces the "Usage of name:" line the usage starts
+// with, e.g. with a description and synopsis.
+func (ndf *NDFlagSet) SetUsageHeader(s string) {
+	ndf.usageHeader = s
+}
+
+// SetUsageFooter - text printed after the flags in the usage, e.g.
+// examples.
+func (ndf *NDFlagSet) SetUsageFooter(s string) {
+	ndf.usageFooter = s
+}
+
 func (ndf *NDFlagSet) ndfUsage() {
 
-	if ndf.name == "" {
+	switch {
+	case ndf.usageHeader != "":
+		fmt.Fprint(ndf.out(), withNewline(ndf.usageHeader))
+	case ndf.name == "":
 		fmt.Fprintf(ndf.out(), "Usage:\n")
-	} else {
+	default:
 		fmt.Fprintf(ndf.out(), "Usage of %s:\n", ndf.name)
 	}
 	ndf.printDefaults()
+	if ndf.usageFooter != "" {
+		fmt.Fprint(ndf.out(), withNewline(ndf.usageFooter))
+	}
+}
+
+func withNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
 }
@@ -1,7 +1,21 @@
 package nodefflag
 
 import (
+	"bytes"
 	"flag"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -332,3 +346,1688 @@ func zvs() *NDFlagSet {
 	fs.ZVDuration("test_duration", time.Second*30, "time.Duration value")
 	return fs
 }
+
+func TestRest(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-n=1", "a", "b"}, `["a" "b"]`},
+		{[]string{"-n=1", "--", "-n=2", "b"}, `["-n=2" "b"]`},
+		{[]string{"--"}, `[]`},
+		{[]string{"-n", "1", "path", "-n", "2", "--", "x"}, `["path" "-n" "2" "--" "x"]`},
+		{[]string{"-n=1"}, `[]`},
+		{[]string{"-", "-n=2"}, `["-" "-n=2"]`},
+	}
+	for _, tt := range tests {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		n := fs.NDInt("n", 0, "n")
+		if err := fs.Parse(tt.args); err != nil {
+			t.Errorf("%q: %v", tt.args, err)
+			continue
+		}
+		if got := fmt.Sprintf("%q", fs.Rest()); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.args, got, tt.want)
+		}
+		if got := fmt.Sprintf("%q", fs.Args()); got != tt.want {
+			t.Errorf("%q: Args got %s, want %s", tt.args, got, tt.want)
+		}
+		if *n != nil && **n != 1 {
+			t.Errorf("%q: flags after the end of the flags were parsed", tt.args)
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	ex := time.Date(2017, 11, 28, 0, 0, 0, 0, time.UTC)
+	ndt := fs.NDTime("nd_time", ex, "time value")
+	ndd := fs.NDTimeLayout("nd_date", "2006-01-02", ex, "date value")
+	zvt := fs.ZVTime("zv_time", ex, "time value")
+	zvd := fs.ZVTimeLayout("zv_date", "2006-01-02", ex, "date value")
+
+	if *ndt != nil || *ndd != nil {
+		t.Error("unset time flags should reference nil")
+	}
+	if d := fs.Lookup("nd_date").DefValue; d != "2017-11-28" {
+		t.Errorf("bad example for nd_date: %q", d)
+	}
+	if d := fs.Lookup("zv_time").DefValue; d != "2017-11-28T00:00:00Z" {
+		t.Errorf("bad example for zv_time: %q", d)
+	}
+
+	err := fs.Parse([]string{
+		"-nd_time=2023-01-15T09:30:00Z",
+		"-nd_date=2023-01-15",
+		"-zv_time=2023-01-15T09:30:00+02:00",
+		"-zv_date=2023-01-16",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(*ndt).Equal(time.Date(2023, 1, 15, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("bad nd_time: %v", *ndt)
+	}
+	if !(*ndd).Equal(time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("bad nd_date: %v", *ndd)
+	}
+	if !zvt.Equal(time.Date(2023, 1, 15, 7, 30, 0, 0, time.UTC)) {
+		t.Errorf("bad zv_time: %v", *zvt)
+	}
+	if !zvd.Equal(time.Date(2023, 1, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("bad zv_date: %v", *zvd)
+	}
+
+	if err := fs.Set("nd_date", "2023-01-15T09:30:00Z"); err == nil {
+		t.Error("expected layout mismatch to fail")
+	}
+}
+
+func TestUnixTime(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDUnixTime("nd_time", time.Unix(1600000000, 0), "time value")
+	zv := fs.ZVUnixTime("zv_time", time.Time{}, "time value")
+
+	if *nd != nil || !zv.IsZero() {
+		t.Error("unset time flags should be nil / zero")
+	}
+	if d := fs.Lookup("nd_time").DefValue; d != "1600000000" {
+		t.Errorf("bad example for nd_time: %q", d)
+	}
+	if d := fs.Lookup("zv_time").DefValue; d != "" {
+		t.Errorf("zero example should be empty: %q", d)
+	}
+
+	tests := []struct {
+		val  string
+		want time.Time
+	}{
+		{"0", time.Unix(0, 0)},
+		{"1600000000", time.Unix(1600000000, 0)},
+		{"1600000000.25", time.Unix(1600000000, 250000000)},
+		{"-86400", time.Unix(-86400, 0)},
+		{"-1.5", time.Unix(-2, 500000000)},
+	}
+	for _, tt := range tests {
+		if err := fs.Parse([]string{"-nd_time", tt.val, "-zv_time", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if !(*nd).Equal(tt.want) || !zv.Equal(tt.want) {
+			t.Errorf("%s: got %v and %v, want %v", tt.val, *nd, *zv, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "abc", "2020-01-01T00:00:00Z", "1e", "NaN", "Inf"} {
+		if err := fs.Parse([]string{"-nd_time=" + bad}); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestIP(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	ndv4 := fs.NDIP("nd_v4", net.IPv4(127, 0, 0, 1), "ipv4 value")
+	ndv6 := fs.NDIP("nd_v6", net.IPv6loopback, "ipv6 value")
+	zv := fs.ZVIP("zv_ip", net.IPv4(127, 0, 0, 1), "ip value")
+
+	if *ndv4 != nil || *ndv6 != nil || *zv != nil {
+		t.Error("unset ip flags should be nil")
+	}
+	if d := fs.Lookup("nd_v6").DefValue; d != "::1" {
+		t.Errorf("bad example for nd_v6: %q", d)
+	}
+
+	err := fs.Parse([]string{"-nd_v4=10.1.2.3", "-nd_v6=fe80::1", "-zv_ip=2001:db8::5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(*ndv4).Equal(net.IPv4(10, 1, 2, 3)) {
+		t.Errorf("bad nd_v4: %v", *ndv4)
+	}
+	if (*ndv6).String() != "fe80::1" {
+		t.Errorf("bad nd_v6: %v", *ndv6)
+	}
+	if zv.String() != "2001:db8::5" {
+		t.Errorf("bad zv_ip: %v", *zv)
+	}
+
+	for _, name := range []string{"nd_v4", "zv_ip"} {
+		if err := fs.Set(name, "10.1.2"); err == nil {
+			t.Errorf("expected invalid ip to fail for %s", name)
+		}
+	}
+}
+
+func TestIPNet(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	_, ex, _ := net.ParseCIDR("192.168.0.0/16")
+	nd := fs.NDIPNet("nd_net", *ex, "cidr value")
+	ndh := fs.NDIPNetHost("nd_host", *ex, "cidr value")
+	zv := fs.ZVIPNet("zv_net", *ex, "cidr value")
+	zvh := fs.ZVIPNetHost("zv_host", *ex, "cidr value")
+
+	if *nd != nil || *ndh != nil {
+		t.Error("unset cidr flags should reference nil")
+	}
+	if d := fs.Lookup("nd_net").DefValue; d != "192.168.0.0/16" {
+		t.Errorf("bad example for nd_net: %q", d)
+	}
+
+	err := fs.Parse([]string{
+		"-nd_net=10.1.2.3/8",
+		"-nd_host=10.1.2.3/8",
+		"-zv_net=2001:db8::1/32",
+		"-zv_host=2001:db8::1/32",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := (*nd).String(); s != "10.0.0.0/8" {
+		t.Errorf("bad nd_net: %s", s)
+	}
+	if s := (*ndh).String(); s != "10.1.2.3/8" {
+		t.Errorf("bad nd_host: %s", s)
+	}
+	if s := zv.String(); s != "2001:db8::/32" {
+		t.Errorf("bad zv_net: %s", s)
+	}
+	if s := zvh.String(); s != "2001:db8::1/32" {
+		t.Errorf("bad zv_host: %s", s)
+	}
+
+	for _, bad := range []string{"10.0.0.0", "10.0.0.0/33", "fe80::/129", "nope/8"} {
+		if err := fs.Set("nd_net", bad); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestAddr(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDAddr("nd_addr", netip.MustParseAddr("127.0.0.1"), "addr value")
+	zv := fs.ZVAddr("zv_addr", netip.Addr{}, "addr value")
+	ndp := fs.NDPrefix("nd_prefix", netip.MustParsePrefix("10.0.0.0/8"), "prefix value")
+	zvp := fs.ZVPrefix("zv_prefix", netip.Prefix{}, "prefix value")
+
+	if *nd != nil || *ndp != nil || zv.IsValid() || zvp.IsValid() {
+		t.Error("unset addr flags should be nil / invalid")
+	}
+	if d := fs.Lookup("nd_addr").DefValue; d != "127.0.0.1" {
+		t.Errorf("bad example for nd_addr: %q", d)
+	}
+	if d := fs.Lookup("nd_prefix").DefValue; d != "10.0.0.0/8" {
+		t.Errorf("bad example for nd_prefix: %q", d)
+	}
+	if d := fs.Lookup("zv_addr").DefValue; d != "" {
+		t.Errorf("zero example should be empty: %q", d)
+	}
+
+	tests := []struct {
+		name, val, want string
+	}{
+		{"nd_addr", "10.1.2.3", "10.1.2.3"},
+		{"nd_addr", "2001:db8::1", "2001:db8::1"},
+		{"nd_addr", "fe80::1%eth0", "fe80::1%eth0"},
+		{"zv_addr", "::ffff:10.1.2.3", "::ffff:10.1.2.3"},
+		{"nd_prefix", "10.1.2.3/8", "10.1.2.3/8"},
+		{"zv_prefix", "2001:db8::/32", "2001:db8::/32"},
+	}
+	for _, tt := range tests {
+		if err := fs.Parse([]string{"-" + tt.name, tt.val}); err != nil {
+			t.Errorf("%s=%s: %v", tt.name, tt.val, err)
+			continue
+		}
+		if got := fs.Lookup(tt.name).Value.(flag.Getter).Get(); fmt.Sprint(got) != tt.want {
+			t.Errorf("%s=%s: got %v", tt.name, tt.val, got)
+		}
+	}
+	if (*nd).Zone() != "eth0" {
+		t.Errorf("bad zone: %q", (*nd).Zone())
+	}
+
+	for _, bad := range [][]string{
+		{"-nd_addr", "10.1.2"},
+		{"-zv_addr", "10.1.2.3/8"},
+		{"-nd_prefix", "10.1.2.3"},
+		{"-zv_prefix", "10.0.0.0/33"},
+		{"-nd_prefix", "fe80::1%eth0/64"},
+	} {
+		if err := fs.Parse(bad); err == nil || !strings.Contains(err.Error(), "invalid value") {
+			t.Errorf("expected %q to fail, got %v", bad, err)
+		}
+	}
+}
+
+func TestAddrPort(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDAddrPort("listen", netip.MustParseAddrPort("127.0.0.1:8080"), "listen address")
+	zv := fs.ZVAddrPort("zv_listen", netip.AddrPort{}, "listen address")
+
+	if *nd != nil || zv.IsValid() {
+		t.Error("unset addrport flags should be nil / invalid")
+	}
+	if d := fs.Lookup("listen").DefValue; d != "127.0.0.1:8080" {
+		t.Errorf("bad example for listen: %q", d)
+	}
+	if d := fs.Lookup("zv_listen").DefValue; d != "" {
+		t.Errorf("zero example should be empty: %q", d)
+	}
+
+	if err := fs.Parse([]string{"-listen=10.0.0.1:80", "-zv_listen", "[::1]:9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if (*nd).Addr() != netip.MustParseAddr("10.0.0.1") || (*nd).Port() != 80 {
+		t.Errorf("bad listen: %v", *nd)
+	}
+	if zv.Addr() != netip.IPv6Loopback() || zv.Port() != 9090 {
+		t.Errorf("bad zv_listen: %v", *zv)
+	}
+
+	def := fs.ZVAddrPortDefault("def_listen", netip.MustParseAddrPort("0.0.0.0:80"), "listen address")
+	if def.Port() != 80 {
+		t.Errorf("default not seeded: %v", *def)
+	}
+
+	for _, bad := range []string{"10.0.0.1", "[::1]", "::1:80", "localhost:80", "10.0.0.1:70000", "10.0.0.1:"} {
+		if err := fs.Parse([]string{"-listen=" + bad}); err == nil || !strings.Contains(err.Error(), "invalid value") {
+			t.Errorf("expected %q to fail, got %v", bad, err)
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	ex, _ := url.Parse("https://example.com/v1")
+	nd := fs.NDURL("nd_url", ex, "url value")
+	nda := fs.NDAbsURL("nd_abs", ex, "url value")
+	zv := fs.ZVURL("zv_url", nil, "url value")
+	zva := fs.ZVAbsURL("zv_abs", ex, "url value")
+
+	if *nd != nil || *nda != nil {
+		t.Error("unset url flags should reference nil")
+	}
+	if d := fs.Lookup("nd_abs").DefValue; d != "https://example.com/v1" {
+		t.Errorf("bad example for nd_abs: %q", d)
+	}
+	if d := fs.Lookup("zv_url").DefValue; d != "" {
+		t.Errorf("bad example for zv_url: %q", d)
+	}
+
+	err := fs.Parse([]string{
+		"-nd_url=../v2/items?id=1",
+		"-nd_abs=https://api.example.com/v2",
+		"-zv_url=/v2",
+		"-zv_abs=http://localhost:8080",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*nd).IsAbs() || (*nd).Path != "../v2/items" || (*nd).Query().Get("id") != "1" {
+		t.Errorf("bad nd_url: %v", *nd)
+	}
+	if (*nda).Host != "api.example.com" || (*nda).Path != "/v2" {
+		t.Errorf("bad nd_abs: %v", *nda)
+	}
+	if zv.String() != "/v2" {
+		t.Errorf("bad zv_url: %v", zv)
+	}
+	if zva.Scheme != "http" || zva.Host != "localhost:8080" {
+		t.Errorf("bad zv_abs: %v", zva)
+	}
+
+	if err := fs.Set("nd_abs", "/relative"); err == nil {
+		t.Error("expected relative url to fail for nd_abs")
+	}
+	if err := fs.Set("zv_abs", "api.example.com"); err == nil {
+		t.Error("expected relative url to fail for zv_abs")
+	}
+	if err := fs.Set("nd_url", "http://[::1"); err == nil {
+		t.Error("expected malformed url to fail")
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDBigInt("nd_big", big.NewInt(65537), "big value")
+	zv := fs.ZVBigInt("zv_big", nil, "big value")
+
+	if *nd != nil || zv.Sign() != 0 {
+		t.Error("unset big flags should be nil / 0")
+	}
+	if d := fs.Lookup("nd_big").DefValue; d != "65537" {
+		t.Errorf("bad example for nd_big: %q", d)
+	}
+
+	huge := "123456789012345678901234567890123456789012345678901234567890"
+	tests := []struct {
+		val, want string
+	}{
+		{"42", "42"},
+		{"-17", "-17"},
+		{"0x10", "16"},
+		{"0b101", "5"},
+		{"0o17", "15"},
+		{huge, huge},
+		{"0x" + strings.Repeat("f", 40), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1)).String()},
+	}
+	for _, tt := range tests {
+		if err := fs.Parse([]string{"-nd_big", tt.val, "-zv_big", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if (*nd).String() != tt.want || zv.String() != tt.want {
+			t.Errorf("%s: got %v and %v, want %s", tt.val, *nd, zv, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "12a", "0x", "1.5", "ten"} {
+		if err := fs.Parse([]string{"-nd_big=" + bad}); err == nil {
+			t.Errorf("expected %q to fail for nd_big", bad)
+		}
+		if err := fs.Parse([]string{"-zv_big=" + bad}); err == nil {
+			t.Errorf("expected %q to fail for zv_big", bad)
+		}
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	nd := fs.NDFloat32("nd_f32", 0.1, "float32 value")
+	zv := fs.ZVFloat32("zv_f32", 0.1, "float32 value")
+
+	if *nd != nil {
+		t.Error("unset nd_f32 should reference nil")
+	}
+	if d := fs.Lookup("nd_f32").DefValue; d != "0.1" {
+		t.Errorf("bad example for nd_f32: %q", d)
+	}
+
+	if err := fs.Parse([]string{"-nd_f32=0.1", "-zv_f32=123.45"}); err != nil {
+		t.Fatal(err)
+	}
+	if **nd != float32(0.1) {
+		t.Errorf("bad nd_f32: %v", **nd)
+	}
+	if *zv != float32(123.45) {
+		t.Errorf("bad zv_f32: %v", *zv)
+	}
+
+	max := strconv.FormatFloat(math.MaxFloat32, 'g', -1, 32)
+	for _, v := range []string{max, "-" + max} {
+		if err := fs.Set("nd_f32", v); err != nil {
+			t.Errorf("unexpected error for %s: %s", v, err)
+		}
+		if math.Abs(float64(**nd)) != math.MaxFloat32 {
+			t.Errorf("bad nd_f32 for %s: %v", v, **nd)
+		}
+	}
+	for _, v := range []string{"3.5e38", "-3.5e38"} {
+		err := fs.Set("zv_f32", v)
+		if ne, ok := err.(*strconv.NumError); !ok || ne.Err != strconv.ErrRange {
+			t.Errorf("expected range error for %s, got %v", v, err)
+		}
+	}
+}
+
+func TestComplex128(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDComplex128("nd_z", 1+2i, "complex value")
+	zv := fs.ZVComplex128("zv_z", 0, "complex value")
+
+	if *nd != nil || *zv != 0 {
+		t.Error("unset complex flags should be nil / 0")
+	}
+	if d := fs.Lookup("nd_z").DefValue; d != "(1+2i)" {
+		t.Errorf("bad example for nd_z: %q", d)
+	}
+
+	tests := []struct {
+		val  string
+		want complex128
+	}{
+		{"3", 3},
+		{"-1.5", -1.5},
+		{"2i", 2i},
+		{"-0.5i", -0.5i},
+		{"1+2i", 1 + 2i},
+		{"(1-2i)", 1 - 2i},
+		{"1e3+1e-3i", 1e3 + 1e-3i},
+	}
+	for _, tt := range tests {
+		if err := fs.Parse([]string{"-nd_z", tt.val, "-zv_z", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if **nd != tt.want || *zv != tt.want {
+			t.Errorf("%s: got %v and %v, want %v", tt.val, **nd, *zv, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "i+", "1+2j", "1+2i+3", "abc"} {
+		if err := fs.Parse([]string{"-nd_z=" + bad}); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestSizedInts(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	i8 := fs.NDInt8("nd_int8", 1, "int8 value")
+	i16 := fs.NDInt16("nd_int16", 2, "int16 value")
+	i32 := fs.NDInt32("nd_int32", 3, "int32 value")
+	zi8 := fs.ZVInt8("zv_int8", 1, "int8 value")
+	zi16 := fs.ZVInt16("zv_int16", 2, "int16 value")
+	zi32 := fs.ZVInt32("zv_int32", 3, "int32 value")
+
+	if *i8 != nil || *i16 != nil || *i32 != nil {
+		t.Error("unset sized int flags should reference nil")
+	}
+
+	tests := []struct {
+		name     string
+		min, max int64
+	}{
+		{"int8", math.MinInt8, math.MaxInt8},
+		{"int16", math.MinInt16, math.MaxInt16},
+		{"int32", math.MinInt32, math.MaxInt32},
+	}
+	for _, tt := range tests {
+		for _, prefix := range []string{"nd_", "zv_"} {
+			name := prefix + tt.name
+			for _, v := range []int64{tt.min, tt.max} {
+				if err := fs.Set(name, strconv.FormatInt(v, 10)); err != nil {
+					t.Errorf("unexpected error for %s=%d: %s", name, v, err)
+				}
+			}
+			for _, v := range []int64{tt.min - 1, tt.max + 1} {
+				err := fs.Set(name, strconv.FormatInt(v, 10))
+				if ne, ok := err.(*strconv.NumError); !ok || ne.Err != strconv.ErrRange {
+					t.Errorf("expected range error for %s=%d, got %v", name, v, err)
+				}
+			}
+		}
+	}
+
+	// the last good value set was the max, out of range sets must not
+	// have clobbered it.
+	if **i8 != math.MaxInt8 || **i16 != math.MaxInt16 || **i32 != math.MaxInt32 {
+		t.Errorf("bad nd values: %d %d %d", **i8, **i16, **i32)
+	}
+	if *zi8 != math.MaxInt8 || *zi16 != math.MaxInt16 || *zi32 != math.MaxInt32 {
+		t.Errorf("bad zv values: %d %d %d", *zi8, *zi16, *zi32)
+	}
+}
+
+func TestSizedUints(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	ui8 := fs.NDUint8("nd_uint8", 1, "uint8 value")
+	ui16 := fs.NDUint16("nd_uint16", 2, "uint16 value")
+	ui32 := fs.NDUint32("nd_uint32", 3, "uint32 value")
+	zui8 := fs.ZVUint8("zv_uint8", 1, "uint8 value")
+	zui16 := fs.ZVUint16("zv_uint16", 2, "uint16 value")
+	zui32 := fs.ZVUint32("zv_uint32", 3, "uint32 value")
+
+	if *ui8 != nil || *ui16 != nil || *ui32 != nil {
+		t.Error("unset sized uint flags should reference nil")
+	}
+
+	tests := []struct {
+		name string
+		max  uint64
+	}{
+		{"uint8", math.MaxUint8},
+		{"uint16", math.MaxUint16},
+		{"uint32", math.MaxUint32},
+	}
+	for _, tt := range tests {
+		for _, prefix := range []string{"nd_", "zv_"} {
+			name := prefix + tt.name
+			if err := fs.Set(name, strconv.FormatUint(tt.max, 10)); err != nil {
+				t.Errorf("unexpected error for %s=%d: %s", name, tt.max, err)
+			}
+			err := fs.Set(name, strconv.FormatUint(tt.max+1, 10))
+			if ne, ok := err.(*strconv.NumError); !ok || ne.Err != strconv.ErrRange {
+				t.Errorf("expected range error for %s=%d, got %v", name, tt.max+1, err)
+			}
+			if err := fs.Set(name, "-1"); err == nil {
+				t.Errorf("expected negative value to fail for %s", name)
+			}
+		}
+	}
+
+	if **ui8 != math.MaxUint8 || **ui16 != math.MaxUint16 || **ui32 != math.MaxUint32 {
+		t.Errorf("bad nd values: %d %d %d", **ui8, **ui16, **ui32)
+	}
+	if *zui8 != math.MaxUint8 || *zui16 != math.MaxUint16 || *zui32 != math.MaxUint32 {
+		t.Errorf("bad zv values: %d %d %d", *zui8, *zui16, *zui32)
+	}
+}
+
+func TestStringSlice(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	nd := fs.NDStringSlice("nd_tag", "repeatable tag")
+	ndUnset := fs.NDStringSlice("nd_unset", "repeatable tag")
+	zv := fs.ZVStringSlice("zv_tag", "repeatable tag")
+	zvUnset := fs.ZVStringSlice("zv_unset", "repeatable tag")
+
+	err := fs.Parse([]string{
+		"-nd_tag=a", "-zv_tag=x", "-nd_tag=b", "-nd_tag", "c", "-zv_tag=", "-nd_tag=",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *ndUnset != nil {
+		t.Errorf("nd_unset should reference nil: %v", **ndUnset)
+	}
+	if *zvUnset == nil || len(*zvUnset) != 0 {
+		t.Errorf("zv_unset should be empty and non-nil: %#v", *zvUnset)
+	}
+	if got := fmt.Sprintf("%q", **nd); got != `["a" "b" "c" ""]` {
+		t.Errorf("bad nd_tag: %s", got)
+	}
+	if got := fmt.Sprintf("%q", *zv); got != `["x" ""]` {
+		t.Errorf("bad zv_tag: %s", got)
+	}
+}
+
+func TestCSVString(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	nd := fs.NDCSVString("nd_hosts", []string{"a", "b"}, "hosts")
+	ndUnset := fs.NDCSVString("nd_unset", nil, "hosts")
+	ndKeep := fs.NDCSVStringOpts("nd_keep", nil, CSVOptions{Sep: ";", KeepEmpty: true}, "hosts")
+	zv := fs.ZVCSVString("zv_hosts", nil, "hosts")
+	zvQuoted := fs.ZVCSVString("zv_quoted", nil, "hosts")
+
+	if d := fs.Lookup("nd_hosts").DefValue; d != "a,b" {
+		t.Errorf("bad example for nd_hosts: %q", d)
+	}
+
+	err := fs.Parse([]string{
+		"-nd_hosts= a, b ,,c,",
+		"-nd_keep=a;;b ; ",
+		"-zv_hosts=x,y",
+		`-zv_quoted="a,b",c`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *ndUnset != nil {
+		t.Error("nd_unset should reference nil")
+	}
+	if got := fmt.Sprintf("%q", **nd); got != `["a" "b" "c"]` {
+		t.Errorf("bad nd_hosts: %s", got)
+	}
+	if got := fmt.Sprintf("%q", **ndKeep); got != `["a" "" "b" ""]` {
+		t.Errorf("bad nd_keep: %s", got)
+	}
+	if got := fmt.Sprintf("%q", *zv); got != `["x" "y"]` {
+		t.Errorf("bad zv_hosts: %s", got)
+	}
+	// unlike encoding/csv, quotes get no special treatment.
+	if got := fmt.Sprintf("%q", *zvQuoted); got != `["\"a" "b\"" "c"]` {
+		t.Errorf("bad zv_quoted: %s", got)
+	}
+
+	// a later occurrence replaces rather than appends.
+	if err := fs.Set("zv_hosts", "z"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprintf("%q", *zv); got != `["z"]` {
+		t.Errorf("bad zv_hosts after reset: %s", got)
+	}
+}
+
+func TestIntSlice(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDIntSlice("nd_id", "repeatable id")
+	zv := fs.ZVIntSlice("zv_id", "repeatable id")
+
+	if *nd != nil || *zv == nil || len(*zv) != 0 {
+		t.Fatal("bad initial int slice state")
+	}
+
+	err := fs.Parse([]string{"-nd_id=1", "-zv_id=-5", "-nd_id=2", "-nd_id=x", "-nd_id=3"})
+	if err == nil {
+		t.Fatal("expected parse error")
+	}
+	if !strings.Contains(err.Error(), `"x"`) || !strings.Contains(err.Error(), "-nd_id") {
+		t.Errorf("error should name the flag and value: %s", err)
+	}
+	if got := fmt.Sprint(**nd); got != "[1 2]" {
+		t.Errorf("bad partial nd_id: %s", got)
+	}
+	if got := fmt.Sprint(*zv); got != "[-5]" {
+		t.Errorf("bad zv_id: %s", got)
+	}
+
+	if err := fs.Set("zv_id", "1.5"); err == nil {
+		t.Error("expected non-integer to fail")
+	}
+	if err := fs.Set("zv_id", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(*zv); got != "[-5 7]" {
+		t.Errorf("bad zv_id: %s", got)
+	}
+}
+
+func TestSizedIntSlice(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDInt32Slice("nd_id", "repeatable id")
+	zv := fs.ZVInt32Slice("zv_id", "repeatable id")
+	small := fs.ZVInt16Slice("small", "repeatable small id")
+
+	if *nd != nil || *zv == nil || len(*zv) != 0 {
+		t.Fatal("bad initial int32 slice state")
+	}
+
+	err := fs.Parse([]string{"-nd_id=1", "-zv_id=-2147483648", "-nd_id=2147483647", "-nd_id=2147483648", "-nd_id=3"})
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Fatalf("expected an out of range error, got %v", err)
+	}
+	if got := fmt.Sprint(**nd); got != "[1 2147483647]" {
+		t.Errorf("bad partial nd_id: %s", got)
+	}
+	if got := fmt.Sprint(*zv); got != "[-2147483648]" {
+		t.Errorf("bad zv_id: %s", got)
+	}
+
+	if err := fs.Set("small", "32768"); err == nil {
+		t.Error("expected int16 overflow to fail")
+	}
+	fs.Set("small", "-3")
+	fs.Set("small", "32767")
+	if got := fmt.Sprint(*small); got != "[-3 32767]" {
+		t.Errorf("bad small: %s", got)
+	}
+}
+
+func TestFloat64Slice(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDFloat64Slice("nd_w", "repeatable weight")
+	zv := fs.ZVFloat64Slice("zv_w", "repeatable weight")
+
+	if *nd != nil || *zv == nil || len(*zv) != 0 {
+		t.Fatal("bad initial float slice state")
+	}
+
+	err := fs.Parse([]string{"-nd_w=0.1", "-zv_w", "-2", "-nd_w=1e3", "-nd_w=x", "-nd_w=3"})
+	if err == nil || !strings.Contains(err.Error(), `"x"`) {
+		t.Fatalf("expected parse error naming the value, got %v", err)
+	}
+	if got := fmt.Sprint(**nd); got != "[0.1 1000]" {
+		t.Errorf("bad partial nd_w: %s", got)
+	}
+	if got := fmt.Sprint(*zv); got != "[-2]" {
+		t.Errorf("bad zv_w: %s", got)
+	}
+
+	for _, bad := range []string{"NaN", "inf", "-Inf", "+infinity", "1e400"} {
+		if err := fs.Set("zv_w", bad); err == nil {
+			t.Errorf("expected %s to fail", bad)
+		}
+	}
+	if got := fmt.Sprint(*zv); got != "[-2]" {
+		t.Errorf("failed values should not be appended: %s", got)
+	}
+}
+
+func TestDurationSlice(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDDurationSlice("retry", "backoff schedule")
+	zv := fs.ZVDurationSlice("zv_retry", "backoff schedule")
+
+	if *nd != nil || *zv == nil || len(*zv) != 0 {
+		t.Fatal("bad initial duration slice state")
+	}
+
+	if err := fs.Parse([]string{"-retry=30s", "-retry=1s", "-zv_retry", "1m", "-retry=5s"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(**nd); got != "[30s 1s 5s]" {
+		t.Errorf("order not preserved: %s", got)
+	}
+	if got := fmt.Sprint(*zv); got != "[1m0s]" {
+		t.Errorf("bad zv_retry: %s", got)
+	}
+
+	fs.Reset()
+	err := fs.Parse([]string{"-retry=1s", "-retry=5", "-retry=30s"})
+	if err == nil || !strings.Contains(err.Error(), `"5"`) || !strings.Contains(err.Error(), "-retry") {
+		t.Fatalf("error should name the flag and value, got %v", err)
+	}
+	if got := fmt.Sprint(**nd); got != "[1s]" {
+		t.Errorf("bad partial retry: %s", got)
+	}
+}
+
+func TestEnum(t *testing.T) {
+	levels := []string{"debug", "info", "warn", "error"}
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	nd := fs.NDEnum("nd_level", levels, "log level")
+	ndUnset := fs.NDEnum("nd_unset", levels, "log level")
+	zv := fs.ZVEnum("zv_level", levels, "log level")
+	fold := fs.ZVEnumOpts("zv_fold", levels, EnumOptions{IgnoreCase: true}, "log level")
+
+	if err := fs.Parse([]string{"-nd_level=warn", "-zv_level=debug", "-zv_fold=INFO"}); err != nil {
+		t.Fatal(err)
+	}
+	if *ndUnset != nil {
+		t.Error("nd_unset should reference nil")
+	}
+	if **nd != "warn" || *zv != "debug" {
+		t.Errorf("bad levels: %s %s", **nd, *zv)
+	}
+	if *fold != "info" {
+		t.Errorf("expected the choice's spelling to be stored, got %s", *fold)
+	}
+
+	err := fs.Set("zv_level", "INFO")
+	if err == nil {
+		t.Fatal("expected case sensitive match to fail")
+	}
+	if want := `invalid choice "INFO", must be one of: debug, info, warn, error`; err.Error() != want {
+		t.Errorf("bad error: %s", err)
+	}
+	if err := fs.Set("nd_level", "trace"); err == nil {
+		t.Error("expected unknown choice to fail")
+	}
+
+	buf := &bytes.Buffer{}
+	fs.SetOutput(buf)
+	fs.Usage()
+	if !strings.Contains(buf.String(), "(one of debug|info|warn|error)") {
+		t.Errorf("usage should list the choices:\n%s", buf)
+	}
+}
+
+func TestRune(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDRune("sep", ',', "separator")
+	zv := fs.ZVRune("zv_sep", '\t', "separator")
+
+	if *nd != nil || *zv != 0 {
+		t.Error("unset rune flags should be nil / 0")
+	}
+	if d := fs.Lookup("sep").DefValue; d != "," {
+		t.Errorf("bad example for sep: %q", d)
+	}
+	if d := fs.Lookup("zv_sep").DefValue; d != `\t` {
+		t.Errorf("bad example for zv_sep: %q", d)
+	}
+
+	tests := []struct {
+		val  string
+		want rune
+	}{
+		{",", ','},
+		{"é", 'é'},
+		{"世", '世'},
+		{`\t`, '\t'},
+		{`\u00e9`, 'é'},
+		{`\U0001F600`, '😀'},
+		{"U+00E9", 'é'},
+		{`\\`, '\\'},
+		{`\`, '\\'},
+	}
+	for _, tt := range tests {
+		if err := fs.Parse([]string{"-sep", tt.val, "-zv_sep", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if **nd != tt.want || *zv != tt.want {
+			t.Errorf("%s: got %q and %q, want %q", tt.val, **nd, *zv, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "ab", "éé", `\tx`, `\uzzzz`, "U+", "U+D800", "U+110000", "\xff"} {
+		if err := fs.Parse([]string{"-sep=" + bad}); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestBoolExtended(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDBoolExtended("nd_bool", false, "bool value")
+	zv := fs.ZVBoolExtended("zv_bool", false, "bool value")
+	strict := fs.NDBool("strict", false, "bool value")
+
+	if *nd != nil || *zv {
+		t.Error("unset bool flags should be nil / false")
+	}
+	tests := []struct {
+		val  string
+		want bool
+	}{
+		{"yes", true}, {"YES", true}, {"y", true}, {"Y", true}, {"on", true}, {"On", true},
+		{"no", false}, {"No", false}, {"n", false}, {"N", false}, {"off", false}, {"OFF", false},
+		{"true", true}, {"1", true}, {"t", true}, {"false", false}, {"0", false}, {"F", false},
+	}
+	for _, tt := range tests {
+		if err := fs.Parse([]string{"-nd_bool=" + tt.val, "-zv_bool=" + tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if **nd != tt.want || *zv != tt.want {
+			t.Errorf("%s: got %v and %v", tt.val, **nd, *zv)
+		}
+	}
+	if err := fs.Parse([]string{"-nd_bool"}); err != nil || !**nd {
+		t.Errorf("bare flag should be true: %v", err)
+	}
+
+	for _, bad := range []string{"-nd_bool=maybe", "-zv_bool=yess", "-strict=yes"} {
+		if err := fs.Parse([]string{bad}); err == nil {
+			t.Errorf("expected %s to fail", bad)
+		}
+	}
+	if *strict != nil {
+		t.Error("strict should not have been set")
+	}
+}
+
+func TestBoolNegatable(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "unset"},
+		{[]string{"-cache"}, "true"},
+		{[]string{"-no-cache"}, "false"},
+		{[]string{"-no-cache=false"}, "true"},
+		{[]string{"-cache", "-no-cache"}, "false"},
+		{[]string{"-no-cache", "-cache=true"}, "true"},
+	}
+	for _, tt := range tests {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		cache := fs.NDBoolNegatable("cache", true, "use the cache")
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		got := "unset"
+		if *cache != nil {
+			got = strconv.FormatBool(**cache)
+		}
+		if got != tt.want {
+			t.Errorf("%v: got %s, want %s", tt.args, got, tt.want)
+		}
+		if fs.IsSet("cache") != (tt.want != "unset") {
+			t.Errorf("%v: bad IsSet", tt.args)
+		}
+	}
+}
+
+func TestFunc(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	var hosts []string
+	fs.NDFunc("host", "host to add", func(val string) error {
+		if val == "" {
+			return fmt.Errorf("empty host")
+		}
+		hosts = append(hosts, strings.ToLower(val))
+		return nil
+	})
+	fs.NDFunc("other", "never given", func(string) error {
+		t.Error("other should not be called")
+		return nil
+	})
+
+	if fs.IsSet("host") {
+		t.Error("host should not be set yet")
+	}
+	if err := fs.Parse([]string{"-host", "A", "-host=b", "-host", "C"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(hosts, ",") != "a,b,c" {
+		t.Errorf("bad hosts: %q", hosts)
+	}
+	if !fs.IsSet("host") || fs.IsSet("other") {
+		t.Error("bad IsSet")
+	}
+	if err := fs.Parse([]string{"-host="}); err == nil || !strings.Contains(err.Error(), "empty host") {
+		t.Errorf("expected the callback's error, got %v", err)
+	}
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"-v"}, 1},
+		{[]string{"-v", "-v", "-v"}, 3},
+		{[]string{"-v=5"}, 5},
+		{[]string{"-v=2", "-v"}, 3},
+		{[]string{"-v", "-v", "-v=false"}, 0},
+	}
+	for _, tt := range tests {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		nd := fs.NDCount("v", "verbosity")
+		zv := fs.ZVCount("zv", "verbosity")
+		zvArgs := make([]string, len(tt.args))
+		for i, a := range tt.args {
+			zvArgs[i] = strings.Replace(a, "-v", "-zv", 1)
+		}
+		if err := fs.Parse(append(tt.args, zvArgs...)); err != nil {
+			t.Fatal(err)
+		}
+		if *nd == nil || **nd != tt.want {
+			t.Errorf("%v: bad nd count %v, want %d", tt.args, *nd, tt.want)
+		}
+		if *zv != tt.want {
+			t.Errorf("%v: bad zv count %d, want %d", tt.args, *zv, tt.want)
+		}
+	}
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	nd := fs.NDCount("v", "verbosity")
+	zv := fs.ZVCount("zv", "verbosity")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if *nd != nil || *zv != 0 {
+		t.Error("absent count flags should be unset")
+	}
+	if err := fs.Set("v", "lots"); err == nil {
+		t.Error("expected bad count to fail")
+	}
+}
+
+func TestBytes(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	nd := fs.NDBytes("nd_size", 1024, "size")
+	zv := fs.ZVBytes("zv_size", 1024, "size")
+	if *nd != nil {
+		t.Error("unset nd_size should reference nil")
+	}
+
+	tests := []struct {
+		val  string
+		want int64
+	}{
+		{"512", 512},
+		{"0", 0},
+		{"512B", 512},
+		{"10KB", 10000},
+		{"10kb", 10000},
+		{"10k", 10000},
+		{"2MiB", 2 << 20},
+		{"2mib", 2 << 20},
+		{"1GB", 1000000000},
+		{"1GiB", 1 << 30},
+		{"1.5KiB", 1536},
+		{"3TB", 3e12},
+		{"1TiB", 1 << 40},
+		{"1PiB", 1 << 50},
+		{"8 MB", 8e6},
+	}
+	for _, tt := range tests {
+		for _, name := range []string{"nd_size", "zv_size"} {
+			if err := fs.Set(name, tt.val); err != nil {
+				t.Errorf("%s=%s: unexpected error: %s", name, tt.val, err)
+			}
+		}
+		if **nd != tt.want || *zv != tt.want {
+			t.Errorf("%s: got %d / %d, want %d", tt.val, **nd, *zv, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "-1", "-1KB", "10XB", "KB", "1.2.3MB", "100000PB"} {
+		if err := fs.Set("nd_size", bad); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestBytesEncoded(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	key := []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0xff}
+	ndh := fs.NDBytesHex("nd_hex", key, "hex value")
+	ndb := fs.NDBytesBase64("nd_b64", key, "base64 value")
+	zvh := fs.ZVBytesHex("zv_hex", nil, "hex value")
+	zvb := fs.ZVBytesBase64("zv_b64", nil, "base64 value")
+
+	if *ndh != nil || *ndb != nil || *zvh != nil || *zvb != nil {
+		t.Error("unset byte flags should be nil")
+	}
+	if d := fs.Lookup("nd_hex").DefValue; d != "deadbeef00ff" {
+		t.Errorf("bad example for nd_hex: %q", d)
+	}
+	if d := fs.Lookup("nd_b64").DefValue; d != "3q2+7wD/" {
+		t.Errorf("bad example for nd_b64: %q", d)
+	}
+
+	err := fs.Parse([]string{"-nd_hex=DEADbeef00ff", "-nd_b64=3q2+7wD/", "-zv_hex=", "-zv_b64=aGk="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(**ndh, key) || !bytes.Equal(**ndb, key) {
+		t.Errorf("bad round trip: %x %x", **ndh, **ndb)
+	}
+	if *zvh == nil || len(*zvh) != 0 {
+		t.Errorf("empty hex should be set and empty: %#v", *zvh)
+	}
+	if string(*zvb) != "hi" {
+		t.Errorf("bad zv_b64: %q", *zvb)
+	}
+
+	for _, bad := range [][]string{
+		{"-nd_hex=abc"},
+		{"-zv_hex=zz"},
+		{"-nd_b64=3q2+7wD"},
+		{"-zv_b64=!!!!"},
+	} {
+		if err := fs.Parse(bad); err == nil || !strings.Contains(err.Error(), "invalid value") {
+			t.Errorf("expected %q to fail, got %v", bad, err)
+		}
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	nd := fs.NDRegexp("nd_filter", "^foo", "filter")
+	unset := fs.NDRegexp("nd_unset", "", "filter")
+	zv := fs.ZVRegexp("zv_filter", ".*", "filter")
+
+	if d := fs.Lookup("nd_filter").DefValue; d != "^foo" {
+		t.Errorf("bad example for nd_filter: %q", d)
+	}
+	if err := fs.Parse([]string{"-nd_filter=^foo.*bar$", "-zv_filter=(?i)baz"}); err != nil {
+		t.Fatal(err)
+	}
+	if *unset != nil {
+		t.Error("nd_unset should reference nil")
+	}
+	if !(*nd).MatchString("foo-bar") || (*nd).MatchString("foo-baz") {
+		t.Errorf("bad nd_filter: %s", *nd)
+	}
+	if !zv.MatchString("BAZ") || zv.String() != "(?i)baz" {
+		t.Errorf("bad zv_filter: %s", zv)
+	}
+	for _, name := range []string{"nd_filter", "zv_filter"} {
+		if err := fs.Set(name, "foo(bar"); err == nil {
+			t.Errorf("expected invalid pattern to fail for %s", name)
+		}
+	}
+}
+
+func TestStringMap(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDStringMap("label", "labels")
+	unset := fs.NDStringMap("unset", "labels")
+	zv := fs.ZVStringMap("zv_label", "labels")
+
+	err := fs.Parse([]string{
+		"-label=env=prod", "-label=team=core", "-label=env=dev", "-label=empty=",
+		"-label=expr=a=b", "-zv_label=k=v",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *unset != nil {
+		t.Error("unset should reference nil")
+	}
+	if got := fmt.Sprint(**nd); got != "map[empty: env:dev expr:a=b team:core]" {
+		t.Errorf("bad label: %s", got)
+	}
+	if got := fmt.Sprint(*zv); got != "map[k:v]" {
+		t.Errorf("bad zv_label: %s", got)
+	}
+
+	if err := fs.Parse([]string{"-zv_label=novalue"}); err == nil {
+		t.Error("expected missing = to fail")
+	}
+	if len(*zv) != 1 {
+		t.Errorf("failed entry should not be stored: %v", *zv)
+	}
+}
+
+// show - formats the result of Get, dereferencing ND pointers and using
+// String() on a copy so value types with pointer receivers print nicely.
+func show(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "<nil>"
+		}
+		rv = rv.Elem()
+	}
+	p := reflect.New(rv.Type())
+	p.Elem().Set(rv)
+	if s, ok := p.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+func TestValueForms(t *testing.T) {
+	tests := []struct {
+		name, val, want string
+	}{
+		{"string", "hello", "hello"},
+		{"int", "-42", "-42"},
+		{"int64", "64", "64"},
+		{"int8", "-8", "-8"},
+		{"uint", "7", "7"},
+		{"uint32", "32", "32"},
+		{"float64", "1.5", "1.5"},
+		{"float32", "2.5", "2.5"},
+		{"bytes", "2KiB", "2048"},
+		{"duration", "90s", "1m30s"},
+		{"time", "2020-01-02T03:04:05Z", "2020-01-02 03:04:05 +0000 UTC"},
+		{"ip", "10.0.0.1", "10.0.0.1"},
+		{"ipnet", "10.1.2.3/8", "10.0.0.0/8"},
+		{"url", "http://example.com/x", "http://example.com/x"},
+		{"regexp", "^a+$", "^a+$"},
+		{"slice", "a", "[a]"},
+		{"ints", "3", "[3]"},
+		{"map", "k=v", "map[k:v]"},
+		{"csv", "a,b", "[a b]"},
+		{"enum", "two", "two"},
+	}
+	register := map[string]func(fs *NDFlagSet){
+		"ND": func(fs *NDFlagSet) {
+			fs.NDString("string", "", "")
+			fs.NDInt("int", 0, "")
+			fs.NDInt64("int64", 0, "")
+			fs.NDInt8("int8", 0, "")
+			fs.NDUint("uint", 0, "")
+			fs.NDUint32("uint32", 0, "")
+			fs.NDFloat64("float64", 0, "")
+			fs.NDFloat32("float32", 0, "")
+			fs.NDBytes("bytes", 0, "")
+			fs.NDDuration("duration", 0, "")
+			fs.NDTime("time", time.Time{}, "")
+			fs.NDIP("ip", nil, "")
+			fs.NDIPNet("ipnet", net.IPNet{}, "")
+			fs.NDURL("url", nil, "")
+			fs.NDRegexp("regexp", "", "")
+			fs.NDStringSlice("slice", "")
+			fs.NDIntSlice("ints", "")
+			fs.NDStringMap("map", "")
+			fs.NDCSVString("csv", nil, "")
+			fs.NDEnum("enum", []string{"one", "two"}, "")
+		},
+		"ZV": func(fs *NDFlagSet) {
+			fs.ZVString("string", "", "")
+			fs.ZVInt("int", 0, "")
+			fs.ZVInt64("int64", 0, "")
+			fs.ZVInt8("int8", 0, "")
+			fs.ZVUint("uint", 0, "")
+			fs.ZVUint32("uint32", 0, "")
+			fs.ZVFloat64("float64", 0, "")
+			fs.ZVFloat32("float32", 0, "")
+			fs.ZVBytes("bytes", 0, "")
+			fs.ZVDuration("duration", 0, "")
+			fs.ZVTime("time", time.Time{}, "")
+			fs.ZVIP("ip", nil, "")
+			fs.ZVIPNet("ipnet", net.IPNet{}, "")
+			fs.ZVURL("url", nil, "")
+			fs.ZVRegexp("regexp", "", "")
+			fs.ZVStringSlice("slice", "")
+			fs.ZVIntSlice("ints", "")
+			fs.ZVStringMap("map", "")
+			fs.ZVCSVString("csv", nil, "")
+			fs.ZVEnum("enum", []string{"one", "two"}, "")
+		},
+	}
+	for family, reg := range register {
+		for _, form := range []string{"space", "equals"} {
+			var args []string
+			for _, tt := range tests {
+				if form == "space" {
+					args = append(args, "-"+tt.name, tt.val)
+				} else {
+					args = append(args, "-"+tt.name+"="+tt.val)
+				}
+			}
+			args = append(args, "rest")
+			fs := NewNDFlagSet(family, flag.ContinueOnError)
+			reg(fs)
+			if err := fs.Parse(args); err != nil {
+				t.Errorf("%s %s: %v", family, form, err)
+				continue
+			}
+			for _, tt := range tests {
+				if got := show(fs.Lookup(tt.name).Value.(flag.Getter).Get()); got != tt.want {
+					t.Errorf("%s %s -%s: got %q, want %q", family, form, tt.name, got, tt.want)
+				}
+			}
+			if fs.NArg() != 1 || fs.Arg(0) != "rest" {
+				t.Errorf("%s %s: bad args %q", family, form, fs.Args())
+			}
+		}
+	}
+}
+
+func TestBoolForms(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+		rest int
+		neg  bool
+	}{
+		{[]string{"-b"}, "true", 0, false},
+		{[]string{"-b=true"}, "true", 0, false},
+		{[]string{"-b=false"}, "false", 0, false},
+		{[]string{"-b=0"}, "false", 0, false},
+		// as with package flag, a bool never consumes the next argument
+		{[]string{"-b", "false"}, "true", 1, false},
+		{[]string{"-no-b"}, "false", 0, true},
+		{[]string{"-b", "-no-b"}, "false", 0, true},
+	}
+	for _, tt := range tests {
+		nd := NewNDFlagSet("ND", flag.ContinueOnError)
+		nb := nd.NDBoolNegatable("b", false, "")
+		zv := NewNDFlagSet("ZV", flag.ContinueOnError)
+		zb := zv.ZVBool("b", false, "")
+		if err := nd.Parse(tt.args); err != nil {
+			t.Errorf("ND %q: %v", tt.args, err)
+		} else if show(*nb) != tt.want || nd.NArg() != tt.rest {
+			t.Errorf("ND %q: got %s with %d args", tt.args, show(*nb), nd.NArg())
+		}
+		if tt.neg {
+			continue
+		}
+		if err := zv.Parse(tt.args); err != nil {
+			t.Errorf("ZV %q: %v", tt.args, err)
+		} else if show(*zb) != tt.want || zv.NArg() != tt.rest {
+			t.Errorf("ZV %q: got %v with %d args", tt.args, *zb, zv.NArg())
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodefflag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	anyp := fs.NDPath("any", "", PathOptions{}, "any path")
+	exists := fs.NDPath("exists", "", PathOptions{MustExist: true}, "existing path")
+	d := fs.NDPath("dir", "", PathOptions{Dir: true}, "directory")
+	f := fs.ZVPath("file", "", PathOptions{File: true}, "regular file")
+	home := fs.ZVPath("home", "", PathOptions{ExpandHome: true}, "path under ~")
+
+	if *anyp != nil || *f != "" {
+		t.Error("unset path flags should be nil / empty")
+	}
+	err = fs.Parse([]string{"-any", missing, "-exists", file, "-dir", dir, "-file", file, "-home", "~/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if **anyp != missing || **exists != file || **d != dir || *f != file {
+		t.Errorf("bad paths: %q %q %q %q", **anyp, **exists, **d, *f)
+	}
+	if h, err := os.UserHomeDir(); err == nil && *home != h+"/x" {
+		t.Errorf("bad expansion: %q", *home)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-exists", missing}, "does not exist"},
+		{[]string{"-dir", missing}, "does not exist"},
+		{[]string{"-dir", file}, "is not a directory"},
+		{[]string{"-file", dir}, "is not a regular file"},
+	} {
+		err := fs.Parse(tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected an error containing %q, got %v", tt.args, tt.want, err)
+		}
+	}
+}
+
+func TestFlexibleIntParsing(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	i := fs.NDInt("int", 0, "int value")
+	i64 := fs.ZVInt64("int64", 0, "int64 value")
+	u := fs.NDUint("uint", 0, "uint value")
+	u8 := fs.ZVUint8("uint8", 0, "uint8 value")
+	is := fs.NDIntSlice("ints", "int values")
+
+	if err := fs.Parse([]string{"-int=0xFF"}); err == nil {
+		t.Errorf("hex should be rejected by default")
+	}
+
+	fs.SetFlexibleIntParsing(true)
+	tests := []struct {
+		val  string
+		want int64
+	}{
+		{"0xFF", 255},
+		{"0o17", 15},
+		{"0O17", 15},
+		{"017", 15},
+		{"010", 8},
+		{"0755", 493},
+		{"0o755", 493},
+		{"0_1", 1},
+		{"0x_FF", 255},
+		{"000", 0},
+		{"0x0F", 15},
+		{"0b101", 5},
+		{"1_000_000", 1000000},
+		{"42", 42},
+	}
+	for _, tt := range tests {
+		args := []string{"-int", tt.val, "-int64", tt.val, "-uint", tt.val, "-ints", tt.val}
+		if err := fs.Parse(args); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if int64(**i) != tt.want || *i64 != tt.want || int64(**u) != tt.want || int64((**is)[len(**is)-1]) != tt.want {
+			t.Errorf("%s: got %d %d %d %v, want %d", tt.val, **i, *i64, **u, **is, tt.want)
+		}
+	}
+	for _, bad := range []string{"-int=0xZZ", "-int=1__0", "-uint=-0x1", "-uint8=0x100"} {
+		if err := fs.Parse([]string{bad}); err == nil {
+			t.Errorf("%s should be rejected", bad)
+		}
+	}
+	if err := fs.Parse([]string{"-int=-010", "-int64=+017"}); err != nil || **i != -8 || *i64 != 15 {
+		t.Errorf("signs should be kept with octal: %d %d, %v", **i, *i64, err)
+	}
+	if err := fs.Parse([]string{"-uint8=0xff"}); err != nil || *u8 != 255 {
+		t.Errorf("bad uint8: %d, %v", *u8, err)
+	}
+}
+
+func TestStringMapItemSep(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	one := fs.NDStringMapOpts("one", MapOptions{ItemSep: ","}, "labels")
+	many := fs.ZVStringMapOpts("many", MapOptions{ItemSep: ","}, "labels")
+	plain := fs.NDStringMap("plain", "labels")
+
+	err := fs.Parse([]string{
+		"-one=env=prod,team=core,",
+		"-many=env=prod", "-many=team=core",
+		"-plain=list=a,b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(**one, *many) || len(*many) != 2 {
+		t.Errorf("one occurrence and repeated ones should match: %v vs %v", **one, *many)
+	}
+	if got := fmt.Sprint(**plain); got != "map[list:a,b]" {
+		t.Errorf("without ItemSep the value is one item: %s", got)
+	}
+
+	err = fs.Parse([]string{"-many=a=1,oops,c=3"})
+	if err == nil || !strings.Contains(err.Error(), `item 2: invalid map entry "oops"`) {
+		t.Errorf("expected an error naming item 2, got %v", err)
+	}
+	if _, ok := (*many)["a"]; ok {
+		t.Errorf("nothing should be stored from a malformed argument: %v", *many)
+	}
+}
+
+func TestUnit(t *testing.T) {
+	units := map[string]float64{"ms": 0.001, "s": 1, "m": 60, "h": 3600}
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDUnit("timeout", units, "s", 30, "timeout")
+	zv := fs.ZVUnit("zv_timeout", units, "s", 0, "timeout")
+
+	if *nd != nil || *zv != 0 {
+		t.Error("unset unit flags should be nil / 0")
+	}
+	if d := fs.Lookup("timeout").DefValue; d != "30s" {
+		t.Errorf("bad example: %q", d)
+	}
+
+	tests := []struct {
+		val  string
+		want float64
+	}{
+		{"500ms", 0.5},
+		{"2s", 2},
+		{"1.5m", 90},
+		{"1h", 3600},
+		{"7", 7},
+		{"-2 s", -2},
+	}
+	for _, tt := range tests {
+		if err := fs.Parse([]string{"-timeout", tt.val, "-zv_timeout", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if **nd != tt.want || *zv != tt.want {
+			t.Errorf("%s: got %v and %v, want %v", tt.val, **nd, *zv, tt.want)
+		}
+	}
+
+	err := fs.Parse([]string{"-timeout=5d"})
+	if err == nil || !strings.Contains(err.Error(), `unknown unit "d" in "5d", must be one of: h, m, ms, s`) {
+		t.Errorf("expected an unknown unit error, got %v", err)
+	}
+	for _, bad := range []string{"ms", "1.2.3s", "5MS"} {
+		if err := fs.Parse([]string{"-timeout", bad}); err == nil {
+			t.Errorf("%s should be rejected", bad)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("a base outside of units should panic")
+		}
+	}()
+	fs.NDUnit("bad", units, "d", 0, "bad base")
+}
+
+func TestPercent(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	reject := fs.NDPercent("reject", 0.25, PercentReject, "sample rate")
+	clamp := fs.ZVPercent("clamp", 0, PercentClamp, "sample rate")
+	allow := fs.ZVPercent("allow", 0, PercentAllow, "growth")
+
+	if *reject != nil || *clamp != 0 {
+		t.Error("unset percent flags should be nil / 0")
+	}
+	if d := fs.Lookup("reject").DefValue; d != "25%" {
+		t.Errorf("bad example: %q", d)
+	}
+
+	for _, tt := range []struct {
+		val  string
+		want float64
+	}{
+		{"0.5", 0.5},
+		{"50%", 0.5},
+		{"12.5 %", 0.125},
+		{"0", 0},
+		{"100%", 1},
+	} {
+		if err := fs.Parse([]string{"-reject", tt.val, "-clamp", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if **reject != tt.want || *clamp != tt.want {
+			t.Errorf("%s: got %v and %v, want %v", tt.val, **reject, *clamp, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		val     string
+		clamped float64
+		allowed float64
+	}{
+		{"150%", 1, 1.5},
+		{"2", 1, 2},
+		{"-10%", 0, -0.1},
+	} {
+		if err := fs.Parse([]string{"-reject", tt.val}); err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("%s: expected an out of range error, got %v", tt.val, err)
+		}
+		if err := fs.Parse([]string{"-clamp", tt.val, "-allow", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if *clamp != tt.clamped || *allow != tt.allowed {
+			t.Errorf("%s: got %v and %v, want %v and %v", tt.val, *clamp, *allow, tt.clamped, tt.allowed)
+		}
+	}
+
+	for _, bad := range []string{"", "%", "abc%", "NaN", "50%%"} {
+		if err := fs.Parse([]string{"-allow", bad}); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDLogLevel("level", LevelWarn, "log level")
+	zv := fs.ZVLogLevel("zlevel", LevelInfo, "log level")
+
+	if *nd != nil || *zv != LevelInfo {
+		t.Error("unset log level flags should be nil / info")
+	}
+	if d := fs.Lookup("level").DefValue; d != "warn" {
+		t.Errorf("bad example: %q", d)
+	}
+
+	for _, tt := range []struct {
+		val  string
+		want LogLevel
+	}{
+		{"debug", LevelDebug},
+		{"INFO", LevelInfo},
+		{"Warn", LevelWarn},
+		{"warning", LevelWarn},
+		{"error", LevelError},
+	} {
+		if err := fs.Parse([]string{"-level", tt.val, "-zlevel", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if **nd != tt.want || *zv != tt.want {
+			t.Errorf("%s: got %v and %v, want %v", tt.val, **nd, *zv, tt.want)
+		}
+	}
+	if !(LevelDebug < LevelInfo && LevelInfo < LevelWarn && LevelWarn < LevelError) {
+		t.Error("levels should be ordered by severity")
+	}
+
+	err := fs.Parse([]string{"-level", "verbose"})
+	if err == nil || !strings.Contains(err.Error(), `unknown log level "verbose"`) {
+		t.Errorf("expected an unknown level error, got %v", err)
+	}
+	if s := LogLevel(7).String(); s != "LogLevel(7)" {
+		t.Errorf("bad String for an unknown level: %q", s)
+	}
+}
+
+func TestEnsureParsedOnce(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	tags := fs.ZVStringSlice("tag", "tags")
+
+	if fs.Parsed() {
+		t.Error("should not be parsed yet")
+	}
+	if err := fs.Parse([]string{"-tag", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-tag", "b"}); err != nil {
+		t.Errorf("without EnsureParsedOnce parsing twice is allowed, got %v", err)
+	}
+
+	fs.Reset()
+	fs.EnsureParsedOnce()
+	if fs.Parsed() {
+		t.Error("Parsed should be false after Reset")
+	}
+	if err := fs.Parse([]string{"-tag", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if !fs.Parsed() {
+		t.Error("Parsed should be true after Parse")
+	}
+	if err := fs.Parse([]string{"-tag", "b"}); err != ErrParsedTwice {
+		t.Errorf("expected ErrParsedTwice, got %v", err)
+	}
+	if len(*tags) != 1 || (*tags)[0] != "a" {
+		t.Errorf("the second parse should not touch the values, got %v", *tags)
+	}
+
+	fs.Reset()
+	if err := fs.Parse([]string{"-tag", "b"}); err != nil {
+		t.Errorf("expected parsing after Reset to work, got %v", err)
+	}
+	if len(*tags) != 1 || (*tags)[0] != "b" {
+		t.Errorf("expected only the new value, got %v", *tags)
+	}
+}
+
+func TestColor(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	nd := fs.NDColor("fg", color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff}, "foreground")
+	zv := fs.ZVColor("bg", color.RGBA{A: 0x80}, "background")
+
+	if *nd != nil || *zv != (color.RGBA{}) {
+		t.Error("unset color flags should be nil / zero")
+	}
+	if d := fs.Lookup("fg").DefValue; d != "#112233" {
+		t.Errorf("bad example: %q", d)
+	}
+	if d := fs.Lookup("bg").DefValue; d != "#00000080" {
+		t.Errorf("bad example: %q", d)
+	}
+
+	for _, tt := range []struct {
+		val  string
+		want color.RGBA
+	}{
+		{"#f80", color.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0xff}},
+		{"#f808", color.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0x88}},
+		{"#1A2b3C", color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}},
+		{"#1a2b3c40", color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0x40}},
+		{"1a2b3c", color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}},
+	} {
+		if err := fs.Parse([]string{"-fg", tt.val, "-bg", tt.val}); err != nil {
+			t.Errorf("%s: %v", tt.val, err)
+			continue
+		}
+		if **nd != tt.want || *zv != tt.want {
+			t.Errorf("%s: got %v and %v, want %v", tt.val, **nd, *zv, tt.want)
+		}
+	}
+
+	for _, val := range []string{"", "#", "#12", "#12345", "#1234567", "#gggggg", "red"} {
+		if err := fs.Parse([]string{"-fg", val}); err == nil {
+			t.Errorf("%q: expected an error", val)
+		}
+	}
+}
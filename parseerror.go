@@ -0,0 +1,51 @@
+package nodefflag
+
+import (
+	"fmt"
+)
+
+// ParseError - returned by ParseStrict when a flag's value fails to
+// parse.  Flag is the canonical name, even if an alias was given.
+type ParseError struct {
+	Flag  string
+	Value string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid value %q for flag -%s: %v", e.Value, e.Flag, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseStrict - same as Parse, but a value that fails to parse is
+// returned as a *ParseError, so you can tell which flag failed and why
+// without matching on the message.  Other errors, like an undefined flag,
+// are returned as the flag package produces them.  The flag set still
+// reports the error and honors its ErrorHandling first, so this is mostly
+// useful with ContinueOnError.
+func (ndf *NDFlagSet) ParseStrict(arguments []string) error {
+	for _, t := range ndf.tracked {
+		t.failErr = nil
+	}
+	if err := ndf.parseArgs(arguments); err != nil {
+		if pe := ndf.setFailure(); pe != nil {
+			return pe
+		}
+		return err
+	}
+	return ndf.postParse()
+}
+
+// setFailure - the failed Set recorded during the last parse, if any.
+func (ndf *NDFlagSet) setFailure() *ParseError {
+	for name, t := range ndf.tracked {
+		if _, ok := ndf.aliases[name]; ok || t.failErr == nil {
+			continue
+		}
+		return &ParseError{Flag: name, Value: t.failVal, Err: t.failErr}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package nodefflag
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"strconv"
+	"testing"
+)
+
+func TestParseStrict(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDInt("port", 0, "port")
+	fs.ZVString("name", "", "name")
+	fs.Alias("port", "p")
+
+	err := fs.ParseStrict([]string{"-name=x", "-p", "80a"})
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+	if pe.Flag != "port" || pe.Value != "80a" {
+		t.Errorf("bad ParseError: %+v", pe)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected to unwrap to ErrSyntax, got %v", pe.Err)
+	}
+	if pe.Error() != `invalid value "80a" for flag -port: strconv.Atoi: parsing "80a": invalid syntax` {
+		t.Errorf("bad message: %s", pe.Error())
+	}
+
+	// a failure from an earlier parse isn't reported again
+	err = fs.ParseStrict([]string{"-nope"})
+	if _, ok := err.(*ParseError); ok || err == nil {
+		t.Errorf("expected a plain error for an undefined flag, got %#v", err)
+	}
+	if err := fs.ParseStrict([]string{"-port=80"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Parse is unchanged
+	if _, ok := fs.Parse([]string{"-port=x"}).(*ParseError); ok {
+		t.Error("Parse should not return a ParseError")
+	}
+}
@@ -0,0 +1,46 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SetAllowPrefixMatch - with on, a flag may be given by any unambiguous
+// prefix of its name, so -verb is taken as -verbose, GNU style.  An exact
+// name always wins, so with -v and -verbose both defined -v is just -v.
+// A prefix matching more than one flag is an error listing them, unless
+// they're all aliases of the same flag.  -h and -help are never expanded,
+// so they still print the usage.
+func (ndf *NDFlagSet) SetAllowPrefixMatch(on bool) {
+	ndf.prefixMatch = on
+}
+
+// prefixFlag - the one flag whose name starts with prefix, nil if there's
+// none.
+func (ndf *NDFlagSet) prefixFlag(prefix string) (*flag.Flag, error) {
+	matched := make(map[string]bool)
+	var names []string
+	ndf.VisitAll(func(fl *flag.Flag) {
+		if !strings.HasPrefix(fl.Name, prefix) {
+			return
+		}
+		names = append(names, "-"+fl.Name)
+		canonical := fl.Name
+		if c, ok := ndf.aliases[fl.Name]; ok {
+			canonical = c
+		}
+		matched[canonical] = true
+	})
+	switch len(matched) {
+	case 0:
+		return nil, nil
+	case 1:
+		for canonical := range matched {
+			return ndf.Lookup(canonical), nil
+		}
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("flag -%s is ambiguous: %s", prefix, strings.Join(names, ", "))
+}
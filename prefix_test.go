@@ -0,0 +1,60 @@
+package nodefflag
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestPrefixMatch(t *testing.T) {
+	newFS := func(on bool) (*NDFlagSet, **bool, **bool, **string, **string) {
+		fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.SetAllowPrefixMatch(on)
+		v := fs.NDBool("v", false, "short")
+		verbose := fs.NDBool("verbose", false, "long")
+		fs.Alias("verbose", "verbosity")
+		output := fs.NDString("output", "", "string value")
+		fs.NDString("outline", "", "string value")
+		host := fs.NDString("host", "", "string value")
+		return fs, v, verbose, output, host
+	}
+
+	fs, v, verbose, output, host := newFS(true)
+	err := fs.Parse([]string{"-verb", "--outp", "-x", "-ho=h", "-v", "rest", "-outp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *verbose == nil || !**verbose {
+		t.Errorf("-verb should set -verbose")
+	}
+	if *output == nil || **output != "-x" {
+		t.Errorf("--outp should take the next argument, got %v", *output)
+	}
+	if *host == nil || **host != "h" {
+		t.Errorf("-ho=h should set -host, got %v", *host)
+	}
+	if *v == nil || !**v {
+		t.Errorf("-v should be exactly -v")
+	}
+	if args := fs.Args(); len(args) != 2 || args[1] != "-outp" {
+		t.Errorf("arguments after the flags shouldn't be touched, got %v", args)
+	}
+
+	fs, _, _, _, _ = newFS(true)
+	err = fs.Parse([]string{"-out=x"})
+	if err == nil || !strings.Contains(err.Error(), "flag -out is ambiguous: -outline, -output") {
+		t.Errorf("expected an ambiguous prefix error, got %v", err)
+	}
+
+	fs, _, _, _, _ = newFS(true)
+	if err := fs.Parse([]string{"-h"}); err != flag.ErrHelp {
+		t.Errorf("-h should still ask for help, got %v", err)
+	}
+
+	fs, _, _, _, _ = newFS(false)
+	if err := fs.Parse([]string{"-verb"}); err == nil {
+		t.Errorf("prefixes should be rejected by default")
+	}
+}
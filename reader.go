@@ -0,0 +1,105 @@
+package nodefflag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseReader - reads arguments from r and passes them to Parse, e.g. to
+// keep a flags.conf with one -name=value per line.  The rules are:
+//
+//   - arguments are separated by any whitespace, including newlines
+//   - lines whose first non-blank character is # are comments
+//   - 'single quotes' keep everything up to the next single quote as is
+//   - "double quotes" do the same, except \" and \\ are unescaped
+//   - outside of quotes, a backslash escapes the next character
+//
+// Quotes can appear anywhere in an argument, so -name="a b" and
+// "-name=a b" are the same.  An argument can't span lines.
+func (ndf *NDFlagSet) ParseReader(r io.Reader) error {
+	args, err := readArgs(r)
+	if err != nil {
+		return ndf.fail(err)
+	}
+	return ndf.Parse(args)
+}
+
+func readArgs(r io.Reader) ([]string, error) {
+	var args []string
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		la, err := splitArgs(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		args = append(args, la...)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// splitArgs - splits a line into arguments, see ParseReader.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args  []string
+		cur   strings.Builder
+		inArg bool
+		quote rune
+	)
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else if c == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+				i++
+				cur.WriteRune(runes[i])
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == '\\':
+			if i+1 < len(runes) {
+				i++
+				cur.WriteRune(runes[i])
+			}
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,48 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseReader(t *testing.T) {
+	conf := `# a comment
+-name="hello world"   -port 8080
+   # an indented comment
+-tag='single "quoted"' -tag=a\ b
+-tag="esc \"d\" \\ \n"
+-tag='' -debug
+positional 'with space'
+`
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	name := fs.NDString("name", "", "name")
+	port := fs.NDInt("port", 0, "port")
+	tags := fs.NDStringSlice("tag", "tags")
+	debug := fs.NDBool("debug", false, "debug")
+
+	if err := fs.ParseReader(strings.NewReader(conf)); err != nil {
+		t.Fatal(err)
+	}
+	if **name != "hello world" || **port != 8080 || !**debug {
+		t.Errorf("bad values: %q %d %v", **name, **port, **debug)
+	}
+	if got := fmt.Sprintf("%q", **tags); got != `["single \"quoted\"" "a b" "esc \"d\" \\ \\n" ""]` {
+		t.Errorf("bad tags: %s", got)
+	}
+	if got := fmt.Sprintf("%q", fs.Args()); got != `["positional" "with space"]` {
+		t.Errorf("bad args: %s", got)
+	}
+}
+
+func TestParseReaderUnterminated(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDString("name", "", "name")
+	err := fs.ParseReader(strings.NewReader("-name=ok\n-name=\"oops\n"))
+	if err == nil || err.Error() != `line 2: unterminated " quote` {
+		t.Errorf("bad error: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package nodefflag
+
+import (
+	"flag"
+)
+
+// Std - returns the embedded flag.FlagSet, for code that wants the
+// stdlib type.  Flags defined on it are defined on ndf, and vice versa.
+func (ndf *NDFlagSet) Std() *flag.FlagSet {
+	return ndf.FlagSet
+}
+
+// CopyTo - registers a copy of every flag, aliases included, on dst, for
+// libraries that only take a *flag.FlagSet.  The copies have targets of
+// their own, as with Clone, and since the stdlib has no notion of "not
+// set" the example becomes a real default: each copy starts out set to
+// it, and it's the DefValue dst reports.  Flags whose example is empty or
+// doesn't parse back start out unset.  Read the values back through
+// dst.Lookup, Get on an ND copy returns the pointer.  Values of your own
+// registered via Var are shared with ndf, and left alone.  Panics if dst
+// already defines one of the names, as the flag package does.
+func (ndf *NDFlagSet) CopyTo(dst *flag.FlagSet) {
+	c := ndf.Clone(dst.Name())
+	c.visitCanonical(func(fl *flag.Flag) {
+		if _, ok := unwrapValue(fl.Value).(cloner); !ok || fl.DefValue == "" {
+			return
+		}
+		// a default, not something given on the command line, so it skips
+		// the transforms, OnSet callbacks and the set count
+		unwrapValue(fl.Value).Set(fl.DefValue)
+	})
+	c.VisitAll(func(fl *flag.Flag) {
+		dst.Var(fl.Value, fl.Name, fl.Usage)
+	})
+}
@@ -0,0 +1,104 @@
+package nodefflag
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStd(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("port", 80, "port")
+	if fs.Std().Lookup("port") == nil || fs.Std() != fs.FlagSet {
+		t.Error("Std should be the embedded FlagSet")
+	}
+}
+
+func TestCopyTo(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	port := fs.NDInt("port", 80, "port")
+	name := fs.ZVString("name", "anon", "name")
+	timeout := fs.NDDuration("timeout", 5*time.Second, "timeout")
+	tags := fs.NDStringSlice("tag", "tags")
+	debug := fs.NDBoolNegatable("debug", false, "debug")
+	host := fs.NDString("host", "", "host")
+	fs.Alias("port", "p")
+
+	dst := flag.NewFlagSet("std", flag.ContinueOnError)
+	dst.SetOutput(ioutil.Discard)
+	fs.CopyTo(dst)
+
+	get := func(name string) interface{} {
+		return dst.Lookup(name).Value.(flag.Getter).Get()
+	}
+	for n, want := range map[string]string{"port": "80", "p": "80", "name": "anon", "timeout": "5s", "debug": "false", "no-debug": "true", "host": ""} {
+		fl := dst.Lookup(n)
+		if fl == nil {
+			t.Errorf("%s not copied", n)
+			continue
+		}
+		if fl.DefValue != want {
+			t.Errorf("%s: bad DefValue %q, want %q", n, fl.DefValue, want)
+		}
+	}
+	if v := get("port").(*int); v == nil || *v != 80 {
+		t.Errorf("port should default to the example, got %v", v)
+	}
+	if v := get("name"); v != "anon" {
+		t.Errorf("name should default to the example, got %v", v)
+	}
+	if v := get("host").(*string); v != nil {
+		t.Errorf("empty example should leave host unset, got %q", *v)
+	}
+
+	if err := dst.Parse([]string{"-p", "8080", "-tag=a", "-no-debug", "rest"}); err != nil {
+		t.Fatal(err)
+	}
+	if v := get("port").(*int); *v != 8080 {
+		t.Errorf("bad copied port: %d", *v)
+	}
+	if v := get("timeout").(*time.Duration); *v != 5*time.Second {
+		t.Errorf("bad copied timeout: %v", *v)
+	}
+	if v := get("tag").(*[]string); v == nil || len(*v) != 1 {
+		t.Errorf("bad copied tag: %v", v)
+	}
+	if v := get("debug").(*bool); v == nil || *v {
+		t.Errorf("bad copied debug: %v", v)
+	}
+	if dst.Arg(0) != "rest" {
+		t.Errorf("bad args: %q", dst.Args())
+	}
+
+	if *port != nil || *name != "" || *timeout != nil || *tags != nil || *debug != nil || *host != nil {
+		t.Error("parsing the copy changed the original")
+	}
+
+	if err := fs.Parse([]string{"-port=1"}); err != nil {
+		t.Fatal(err)
+	}
+	if v := get("port").(*int); *v != 8080 {
+		t.Errorf("parsing the original changed the copy: %d", *v)
+	}
+}
+
+func TestCopyToSkipsHooks(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("port", 80, "port")
+	fs.ZVString("name", "anon", "name")
+	calls := 0
+	fs.OnSet("port", func(interface{}) { calls++ })
+	fs.OnSet("name", func(interface{}) { calls++ })
+	fs.AddTransform("name", strings.ToUpper)
+
+	dst := flag.NewFlagSet("std", flag.ContinueOnError)
+	fs.CopyTo(dst)
+	if calls != 0 {
+		t.Errorf("seeding the defaults shouldn't fire OnSet, got %d calls", calls)
+	}
+	if v := dst.Lookup("name").Value.(flag.Getter).Get(); v != "anon" {
+		t.Errorf("seeding the defaults shouldn't transform, got %v", v)
+	}
+}
@@ -0,0 +1,73 @@
+package nodefflag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SetStdinSentinel - with on, a value of "-" for a string, hex or base64
+// flag means read the value from stdin instead, e.g. -password - to have
+// a secret piped in rather than show up in ps.  Stdin is read to EOF the
+// first time it's needed and kept, so every flag given - gets the same
+// value, and a single trailing newline is dropped, as left by echo.  Off
+// by default, since "-" is a perfectly good string.
+func (ndf *NDFlagSet) SetStdinSentinel(on bool) {
+	ndf.stdinDash = on
+	if on && ndf.stdin == nil {
+		ndf.stdin = &stdinSrc{r: os.Stdin}
+	}
+}
+
+// SetStdin - sets where SetStdinSentinel reads a "-" value from, e.g. a
+// strings.Reader in tests.  nil means os.Stdin, the default.  Like stdin,
+// it's read at most once.
+func (ndf *NDFlagSet) SetStdin(r io.Reader) {
+	if r == nil {
+		r = os.Stdin
+	}
+	ndf.stdin = &stdinSrc{r: r}
+}
+
+// stdinSrc - where a "-" value is read from, read at most once.  Clones
+// share it with the flag set they came from, as stdin can't be read
+// twice.
+type stdinSrc struct {
+	r    io.Reader
+	once sync.Once
+	val  string
+	err  error
+}
+
+func (s *stdinSrc) read() (string, error) {
+	s.once.Do(func() {
+		b, err := io.ReadAll(s.r)
+		if err != nil {
+			s.err = fmt.Errorf("reading stdin: %w", err)
+			return
+		}
+		v := strings.TrimSuffix(string(b), "\n")
+		s.val = strings.TrimSuffix(v, "\r")
+	})
+	return s.val, s.err
+}
+
+// stdinTaker - implemented by the generic Value types, true for those
+// SetStdinSentinel applies to.
+type stdinTaker interface {
+	takesStdin() bool
+}
+
+// fromStdin - val, or what's on stdin if val is the sentinel and v takes
+// it.
+func (ndf *NDFlagSet) fromStdin(v interface{}, val string) (string, error) {
+	if !ndf.stdinDash || val != "-" {
+		return val, nil
+	}
+	if s, ok := v.(stdinTaker); !ok || !s.takesStdin() {
+		return val, nil
+	}
+	return ndf.stdin.read()
+}
@@ -0,0 +1,48 @@
+package nodefflag
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestStdinSentinel(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	pass := fs.NDString("pass", "", "password")
+	again := fs.ZVString("again", "", "password again")
+	fs.ZVInt("port", 0, "port")
+
+	// off, - is just a string.
+	if err := fs.Parse([]string{"-pass", "-"}); err != nil {
+		t.Fatal(err)
+	}
+	if **pass != "-" {
+		t.Errorf("expected a literal -, got %q", **pass)
+	}
+
+	fs.Reset()
+	fs.SetStdinSentinel(true)
+	fs.SetStdin(strings.NewReader("s3cret\n"))
+	if err := fs.Parse([]string{"-pass", "-", "-again", "-"}); err != nil {
+		t.Fatal(err)
+	}
+	if **pass != "s3cret" || *again != "s3cret" {
+		t.Errorf("expected both flags read from stdin, got %q and %q", **pass, *again)
+	}
+	if err := fs.Parse([]string{"-port", "-"}); err == nil {
+		t.Errorf("non-string flags shouldn't read stdin")
+	}
+
+	fs = NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	key := fs.NDBytesHex("key", nil, "key")
+	fs.SetStdin(strings.NewReader("beef\r\n"))
+	fs.SetStdinSentinel(true)
+	if err := fs.Parse([]string{"-key", "-"}); err != nil {
+		t.Fatal(err)
+	}
+	if string(**key) != "\xbe\xef" {
+		t.Errorf("expected the hex key from stdin, got %x", **key)
+	}
+}
@@ -0,0 +1,291 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+)
+
+// trackedValue wraps every Value registered through NDFlagSet.Var, so
+// the flag set can tell whether a flag was set independent of the value
+// type.  This is what gives the ZV variants set / unset detection.
+type trackedValue struct {
+	flag.Value
+	// successful Sets, see SetCount.
+	count int
+	// the argument and error of the last failed Set, for ParseStrict.
+	failVal string
+	failErr error
+	// run on the argument before it's passed on, see AddTransform.
+	transforms []func(string) string
+	// the flag set the value was registered with, for its settings.
+	owner *NDFlagSet
+	// called with Get() after every successful Set, see OnSet.
+	onSet []func(interface{})
+	// what a ZV target held when defined, for the usage, see usageValue.
+	start             string
+	hasStart, startOK bool
+}
+
+func (t *trackedValue) String() string {
+	// the flag package builds zero values of the Value type via
+	// reflection when printing defaults, so tolerate a nil Value.
+	if t.Value == nil {
+		return ""
+	}
+	return t.Value.String()
+}
+
+func (t *trackedValue) Set(val string) error {
+	if t.owner != nil {
+		val = t.owner.expandString(t.Value, val)
+	}
+	for _, fn := range t.transforms {
+		val = fn(val)
+	}
+	if t.owner != nil {
+		var err error
+		if val, err = t.owner.fromStdin(t.Value, val); err != nil {
+			t.failVal, t.failErr = "-", err
+			return err
+		}
+		if t.owner.flexInts {
+			val = flexibleInt(t.Value, val)
+		}
+	}
+	if err := t.Value.Set(val); err != nil {
+		t.failVal, t.failErr = val, err
+		return err
+	}
+	t.count++
+	for _, fn := range t.onSet {
+		fn(t.Get())
+	}
+	return nil
+}
+
+func (t *trackedValue) Get() interface{} {
+	if g, ok := t.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return nil
+}
+
+func (t *trackedValue) IsBoolFlag() bool {
+	b, ok := t.Value.(interface {
+		IsBoolFlag() bool
+	})
+	return ok && b.IsBoolFlag()
+}
+
+// resetter - implemented by the package's Value types, puts the target
+// back in its unset state.
+type resetter interface {
+	reset()
+}
+
+func (t *trackedValue) reset() {
+	if r, ok := t.Value.(resetter); ok {
+		r.reset()
+	}
+	t.count = 0
+}
+
+// unwrapValue - returns the Value registered by the caller, rather than
+// our tracking wrapper around it.
+func unwrapValue(v flag.Value) flag.Value {
+	if t, ok := v.(*trackedValue); ok {
+		return t.Value
+	}
+	return v
+}
+
+// Var - same as flag.FlagSet.Var, but the flag set tracks whether the
+// flag was set.  All of the ND and ZV methods register through here.
+func (ndf *NDFlagSet) Var(value flag.Value, name, usage string) {
+	t := &trackedValue{Value: value, owner: ndf}
+	if s, ok := value.(starter); ok {
+		t.hasStart = true
+		t.start, t.startOK = s.startValue()
+	}
+	ndf.FlagSet.Var(t, name, usage)
+	if ndf.tracked == nil {
+		ndf.tracked = make(map[string]*trackedValue)
+	}
+	ndf.tracked[name] = t
+	ndf.declared = append(ndf.declared, name)
+}
+
+// AddTransform - attaches fn to the named flag, to rewrite every argument
+// given for it before the flag parses it, e.g. strings.TrimSpace or
+// expanding ~ in a path.  Transforms run in the order added, then the
+// value is parsed, then any validators run on the result.  Panics if the
+// flag isn't defined, or was defined directly on the embedded FlagSet.
+func (ndf *NDFlagSet) AddTransform(name string, fn func(string) string) {
+	t, ok := ndf.tracked[name]
+	if !ok {
+		panic(fmt.Sprintf("flag transform for undefined flag -%s", name))
+	}
+	t.transforms = append(t.transforms, fn)
+}
+
+// OnSet - attaches fn to the named flag, to be called with its Get()
+// result every time it's set, right as it happens, e.g. to change the log
+// level the moment -log-level is parsed rather than after Parse.  For the
+// repeatable flags that's once per occurrence.  Failed Sets don't call
+// fn.  Panics if the flag isn't defined, or was defined directly on the
+// embedded FlagSet.
+func (ndf *NDFlagSet) OnSet(name string, fn func(value interface{})) {
+	t, ok := ndf.tracked[name]
+	if !ok {
+		panic(fmt.Sprintf("flag callback for undefined flag -%s", name))
+	}
+	t.onSet = append(t.onSet, fn)
+}
+
+// IsSet - reports whether the named flag was set, either on the command
+// line or via Set.  Returns false for flags that were never set and for
+// unknown names.  This is mostly useful for the ZV variants, where the
+// zero value doesn't tell you whether the flag was given.
+func (ndf *NDFlagSet) IsSet(name string) bool {
+	if t, ok := ndf.tracked[name]; ok {
+		return t.count > 0
+	}
+	// registered directly on the embedded FlagSet, fall back to what
+	// the flag package knows.
+	set := false
+	ndf.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// SetFlag - sets the named flag from value as if it were given on the
+// command line, transforms included, so it counts for IsSet and SetCount,
+// e.g. to inject values in tests.  The embedded FlagSet's Set does the
+// same, as every Value registered through this package is tracked, this
+// is just the explicit spelling.  Unknown names are an error.
+func (ndf *NDFlagSet) SetFlag(name, value string) error {
+	if ndf.Lookup(name) == nil {
+		return fmt.Errorf("no such flag -%s", name)
+	}
+	return ndf.FlagSet.Set(name, value)
+}
+
+// WasDefaulted - reports whether the named ZV flag kept its default, be
+// that the zero value or one given to a ZV*Default method, i.e. it
+// wasn't set.  The inverse of IsSet for ZV flags; false for unknown names
+// and for ND flags, which have an example rather than a default.
+func (ndf *NDFlagSet) WasDefaulted(name string) bool {
+	fl := ndf.Lookup(name)
+	if fl == nil {
+		return false
+	}
+	if _, ok := unwrapValue(fl.Value).(zeroValuer); !ok {
+		return false
+	}
+	return !ndf.IsSet(name)
+}
+
+// SetCount - how many times the named flag was set, on the command line
+// or via Set, e.g. to tell -v from -v -v, or to catch a scalar flag given
+// twice.  Failed Sets don't count, and aliases share the count of their
+// flag.  Returns 0 for unset and unknown flags, and at most 1 for flags
+// defined directly on the embedded FlagSet, as that's all the flag
+// package remembers.
+func (ndf *NDFlagSet) SetCount(name string) int {
+	if t, ok := ndf.tracked[name]; ok {
+		return t.count
+	}
+	if ndf.IsSet(name) {
+		return 1
+	}
+	return 0
+}
+
+// Reset - puts every flag back in its unset state, so the flag set can be
+// parsed again with fresh arguments: ND double pointers reference nil
+// again, ZV values go back to their zero value, and IsSet reports false.
+// The targets are reset in place rather than reallocated, so pointers
+// handed out earlier see the reset.  Values of your own registered via
+// Var are only forgotten as set, not changed, and so are flags defined
+// directly on the embedded FlagSet.  Parsed reports false again.
+func (ndf *NDFlagSet) Reset() {
+	ndf.parsed = false
+	for _, t := range ndf.tracked {
+		t.reset()
+	}
+	ndf.resetStd()
+}
+
+// resetStd - makes the embedded FlagSet forget which flags were set,
+// which the flag package has no method for, by rebuilding it with the
+// same flags.  It's rebuilt in place, so the pointer Std returns stays
+// good.
+func (ndf *NDFlagSet) resetStd() {
+	fs := flag.NewFlagSet(ndf.FlagSet.Name(), ndf.FlagSet.ErrorHandling())
+	fs.SetOutput(ndf.FlagSet.Output())
+	fs.Usage = ndf.FlagSet.Usage
+	ndf.FlagSet.VisitAll(func(fl *flag.Flag) {
+		fs.Var(fl.Value, fl.Name, fl.Usage)
+		// Var takes the current value, which may not be the default
+		fs.Lookup(fl.Name).DefValue = fl.DefValue
+	})
+	*ndf.FlagSet = *fs
+}
+
+// materializer - implemented by the ND scalar Value types, points the
+// target at the parsed example if it's still nil.
+type materializer interface {
+	materialize()
+}
+
+// MaterializeDefaults - call after Parse, points every unset ND scalar
+// flag at its default if it was defined with one of the ND*Default
+// methods, or else at its example, for code that would rather not nil
+// check.  Flags that were set are left alone, as are ND flags without a
+// usable example: slices, maps, counts, and examples that don't parse,
+// e.g. an empty one for NDInt.  IsSet still reports false for the
+// materialized flags.
+func (ndf *NDFlagSet) MaterializeDefaults() {
+	for name, t := range ndf.tracked {
+		if m, ok := t.Value.(materializer); ok && !ndf.IsSet(name) {
+			m.materialize()
+		}
+	}
+}
+
+// resolver - implemented by the ND scalar Value types, points the target
+// at the result of its default func if it has one and is still nil.
+type resolver interface {
+	resolve()
+}
+
+// ResolveDefaults - call after Parse, points every unset flag defined
+// with NDStringFunc at what its default func returns.  The funcs run
+// here, not when the flags are defined, and only for the flags that
+// weren't set, so an expensive default costs nothing when given on the
+// command line.  IsSet still reports false for the resolved flags.
+// MaterializeDefaults resolves them as well.
+func (ndf *NDFlagSet) ResolveDefaults() {
+	for name, t := range ndf.tracked {
+		if r, ok := t.Value.(resolver); ok && !ndf.IsSet(name) {
+			r.resolve()
+		}
+	}
+}
+
+// LookupValue - returns the Value registered for the named flag, e.g. to
+// call Get on it without going through Lookup and a type assertion.  For
+// flags registered through this package that's the ND / ZV value itself,
+// not the wrapper the flag set tracks it with, so calling Set on it
+// doesn't count for IsSet, use the flag set's Set for that.  Returns nil,
+// false for unknown names.
+func (ndf *NDFlagSet) LookupValue(name string) (flag.Value, bool) {
+	fl := ndf.Lookup(name)
+	if fl == nil {
+		return nil, false
+	}
+	return unwrapValue(fl.Value), true
+}
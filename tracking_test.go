@@ -0,0 +1,352 @@
+package nodefflag
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsSet(t *testing.T) {
+	fs := zvs()
+	fs.NDString("nd_string", "test", "string value")
+	fs.Bool("std_bool", false, "stdlib bool value")
+
+	err := fs.Parse([]string{"-test_int=0", "-test_bool", "-nd_string=", "-std_bool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"test_int", "test_bool", "nd_string", "std_bool"} {
+		if !fs.IsSet(name) {
+			t.Errorf("expected %s to be set", name)
+		}
+	}
+	for _, name := range []string{"test_int64", "test_string", "test_duration", "nope", ""} {
+		if fs.IsSet(name) {
+			t.Errorf("expected %s to not be set", name)
+		}
+	}
+
+	if err := fs.Set("test_string", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !fs.IsSet("test_string") {
+		t.Error("expected test_string to be set via Set")
+	}
+
+	// a failed Set doesn't count.
+	if err := fs.Set("test_uint", "-1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if fs.IsSet("test_uint") {
+		t.Error("expected test_uint to not be set after failed Set")
+	}
+}
+
+func TestTrackedBoolFlag(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	bv := fs.NDBool("b", false, "bool value")
+	iv := fs.NDInt("i", 0, "int value")
+
+	if err := fs.Parse([]string{"-b", "-i", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if *bv == nil || !**bv {
+		t.Error("expected -b alone to set true")
+	}
+	if *iv == nil || **iv != 3 {
+		t.Error("expected -i 3 to consume the next argument")
+	}
+}
+
+func TestReset(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	ndInt := fs.NDInt("nd_int", 1, "int value")
+	zvStr := fs.ZVString("zv_string", "x", "string value")
+	tags := fs.NDStringSlice("tag", "tags")
+	zvTags := fs.ZVStringSlice("zv_tag", "tags")
+	count := fs.ZVCount("v", "verbosity")
+
+	if err := fs.Parse([]string{"-nd_int=5", "-zv_string=a", "-tag=a", "-zv_tag=b", "-v", "-v"}); err != nil {
+		t.Fatal(err)
+	}
+	fs.Reset()
+
+	if *ndInt != nil || *tags != nil {
+		t.Error("nd flags should reference nil after reset")
+	}
+	if *zvStr != "" || *count != 0 || *zvTags == nil || len(*zvTags) != 0 {
+		t.Error("zv flags should be back to their zero values after reset")
+	}
+	for _, name := range []string{"nd_int", "zv_string", "tag", "zv_tag", "v"} {
+		if fs.IsSet(name) {
+			t.Errorf("%s should not be set after reset", name)
+		}
+	}
+
+	if err := fs.Parse([]string{"-zv_string=b", "-tag=c", "-v"}); err != nil {
+		t.Fatal(err)
+	}
+	if *ndInt != nil || fs.IsSet("nd_int") {
+		t.Error("nd_int should stay unset on re-parse")
+	}
+	if *zvStr != "b" || len(**tags) != 1 || (**tags)[0] != "c" || *count != 1 {
+		t.Errorf("bad values after re-parse: %q %q %d", *zvStr, **tags, *count)
+	}
+}
+
+func TestResetStd(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	std := fs.Std()
+	raw := fs.FlagSet.String("raw", "def", "defined on the embedded FlagSet")
+
+	if err := fs.Parse([]string{"-raw=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if !fs.IsSet("raw") || fs.SetCount("raw") != 1 {
+		t.Fatal("raw should be set")
+	}
+	fs.Reset()
+	if fs.IsSet("raw") || fs.SetCount("raw") != 0 {
+		t.Error("raw should not be set after reset")
+	}
+	if fs.Std() != std {
+		t.Error("the embedded FlagSet should be reset in place")
+	}
+	if d := fs.Lookup("raw").DefValue; d != "def" {
+		t.Errorf("DefValue should survive the reset, got %q", d)
+	}
+	if err := fs.Parse([]string{"-raw=y"}); err != nil {
+		t.Fatal(err)
+	}
+	if *raw != "y" || !fs.IsSet("raw") {
+		t.Errorf("raw should be set again, got %q", *raw)
+	}
+}
+
+func TestLookupValue(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	port := fs.NDInt("port", 0, "port")
+	name := fs.ZVString("name", "", "name")
+
+	if v, ok := fs.LookupValue("nope"); ok || v != nil {
+		t.Errorf("expected nil, false for unknown flag, got %v, %v", v, ok)
+	}
+
+	v, ok := fs.LookupValue("port")
+	if !ok {
+		t.Fatal("port not found")
+	}
+	if _, ok := v.(*trackedValue); ok {
+		t.Error("should not return the tracking wrapper")
+	}
+	g := v.(flag.Getter)
+	if p := g.Get().(*int); p != nil {
+		t.Errorf("port should be unset, got %d", *p)
+	}
+	if err := v.Set("8080"); err != nil {
+		t.Fatal(err)
+	}
+	if **port != 8080 || *g.Get().(*int) != 8080 {
+		t.Errorf("bad port: %d", **port)
+	}
+
+	if err := fs.Parse([]string{"-name=x"}); err != nil {
+		t.Fatal(err)
+	}
+	v, _ = fs.LookupValue("name")
+	if got := v.(flag.Getter).Get(); got != "x" || *name != "x" {
+		t.Errorf("bad name: %v", got)
+	}
+}
+
+func TestAddTransform(t *testing.T) {
+	home := "/home/gopher"
+	expand := func(val string) string {
+		if val == "~" || strings.HasPrefix(val, "~/") {
+			return home + val[1:]
+		}
+		return val
+	}
+
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	name := fs.NDString("name", "", "name")
+	dirs := fs.ZVStringSlice("dir", "dirs")
+	port := fs.NDInt("port", 0, "port")
+	fs.Alias("dir", "d")
+	fs.AddTransform("name", strings.TrimSpace)
+	fs.AddTransform("name", strings.ToLower)
+	fs.AddTransform("dir", strings.TrimSpace)
+	fs.AddTransform("dir", expand)
+	fs.AddTransform("port", strings.TrimSpace)
+	fs.AddValidator("name", func(v interface{}) error {
+		if s := *v.(*string); s != strings.ToLower(s) {
+			return fmt.Errorf("validator saw the untransformed value %q", s)
+		}
+		return nil
+	})
+
+	err := fs.Parse([]string{"-name", "  Gopher ", "-dir= ~/src", "-d", "~", "-dir=/tmp/~x", "-port= 80 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if **name != "gopher" {
+		t.Errorf("bad name: %q", **name)
+	}
+	if got := strings.Join(*dirs, ","); got != "/home/gopher/src,/home/gopher,/tmp/~x" {
+		t.Errorf("bad dirs: %s", got)
+	}
+	if **port != 80 {
+		t.Errorf("bad port: %d", **port)
+	}
+
+	c := fs.Clone("clone")
+	if err := c.Parse([]string{"-name= X "}); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.StringValue("name"); v != "x" {
+		t.Errorf("clone should keep the transforms, got %q", v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an undefined flag")
+		}
+	}()
+	fs.AddTransform("nope", strings.TrimSpace)
+}
+
+func TestSetCount(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDString("once", "", "string value")
+	fs.ZVInt("never", 0, "int value")
+	fs.NDBool("v", false, "verbose")
+	fs.Alias("v", "verbose")
+	fs.NDStringSlice("tag", "repeatable")
+	fs.FlagSet.String("std", "", "plain flag")
+
+	err := fs.Parse([]string{"-once=x", "-v", "-verbose", "-v", "-tag=a", "-tag=b", "-std=y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]int{
+		"once": 1, "never": 0, "v": 3, "verbose": 3, "tag": 2, "std": 1, "nope": 0,
+	} {
+		if got := fs.SetCount(name); got != want {
+			t.Errorf("SetCount(%q) = %d, want %d", name, got, want)
+		}
+	}
+
+	if err := fs.Set("never", "x"); err == nil {
+		t.Errorf("expected an error setting -never to x")
+	}
+	if n := fs.SetCount("never"); n != 0 {
+		t.Errorf("a failed Set shouldn't count, got %d", n)
+	}
+	fs.Reset()
+	if n := fs.SetCount("tag"); n != 0 {
+		t.Errorf("Reset should clear the count, got %d", n)
+	}
+}
+
+func TestMaterializeDefaults(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	host := fs.NDString("host", "localhost", "string value")
+	port := fs.NDInt("port", 8080, "int value")
+	debug := fs.NDBool("debug", false, "bool value")
+	timeout := fs.NDDuration("timeout", 5*time.Second, "duration value")
+	tags := fs.NDStringSlice("tag", "slice value")
+	zv := fs.ZVInt("zv", 3, "int value")
+
+	if err := fs.Parse([]string{"-port=9090"}); err != nil {
+		t.Fatal(err)
+	}
+	fs.MaterializeDefaults()
+
+	if *host == nil || **host != "localhost" {
+		t.Errorf("host should be materialized, got %v", *host)
+	}
+	if *port == nil || **port != 9090 {
+		t.Errorf("port was set, should be untouched, got %v", *port)
+	}
+	if *debug == nil || **debug {
+		t.Errorf("debug should be materialized to false, got %v", *debug)
+	}
+	if *timeout == nil || **timeout != 5*time.Second {
+		t.Errorf("timeout should be materialized, got %v", *timeout)
+	}
+	if *tags != nil {
+		t.Errorf("slices have no example, got %v", **tags)
+	}
+	if *zv != 0 {
+		t.Errorf("ZV flags should be left alone, got %d", *zv)
+	}
+	if fs.IsSet("host") {
+		t.Errorf("a materialized flag shouldn't count as set")
+	}
+}
+
+func TestSetFlag(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	name := fs.NDString("name", "", "string value")
+	port := fs.ZVInt("port", 0, "int value")
+	fs.AddTransform("name", strings.TrimSpace)
+
+	if err := fs.SetFlag("name", " x "); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.SetFlag("port", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if *name == nil || **name != "x" || !fs.IsSet("name") {
+		t.Errorf("name should be set to x, got %v", *name)
+	}
+	if *port != 0 || !fs.IsSet("port") {
+		t.Errorf("port should be set, even to its zero value")
+	}
+	if err := fs.SetFlag("port", "x"); err == nil {
+		t.Errorf("expected an error for a bad value")
+	}
+	if err := fs.SetFlag("nope", "x"); err == nil || err.Error() != "no such flag -nope" {
+		t.Errorf("expected an error for an unknown flag, got %v", err)
+	}
+}
+
+func TestOnSet(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.NDString("level", "", "string value")
+	fs.NDStringSlice("tag", "string slice")
+	fs.ZVInt("port", 0, "int value")
+
+	var level []interface{}
+	var tags int
+	fs.OnSet("level", func(v interface{}) { level = append(level, v) })
+	fs.OnSet("tag", func(interface{}) { tags++ })
+	fs.OnSet("port", func(interface{}) { t.Errorf("callback for failed set") })
+
+	if err := fs.Parse([]string{"-level", "debug", "-tag", "a", "-tag", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(level) != 1 || *level[0].(*string) != "debug" {
+		t.Errorf("expected one debug callback, got %v", level)
+	}
+	if tags != 2 {
+		t.Errorf("expected a callback per occurrence, got %d", tags)
+	}
+	if err := fs.Parse([]string{"-port", "x"}); err == nil {
+		t.Errorf("expected an error for a bad value")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for an undefined flag")
+		}
+	}()
+	fs.OnSet("nope", func(interface{}) {})
+}
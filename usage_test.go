@@ -0,0 +1,221 @@
+package nodefflag
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func usage(fs *NDFlagSet) string {
+	buf := &bytes.Buffer{}
+	fs.SetOutput(buf)
+	fs.Usage()
+	return buf.String()
+}
+
+func TestUsageQuotesStrings(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("nd_string", "nd example", "nd string")
+	fs.ZVString("zv_string", "zv example", "zv string")
+	fs.ZVRegexp("zv_regexp", "^a b$", "zv regexp")
+	fs.NDInt("nd_int", 5, "nd int")
+
+	out := usage(fs)
+	for _, want := range []string{
+		`nd string (example "nd example")`,
+		`zv string (default "")`,
+		`nd int (example 5)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("usage missing %q:\n%s", want, out)
+		}
+	}
+	// a nil regexp has nothing worth showing
+	if !strings.Contains(out, "zv regexp\n") {
+		t.Errorf("zv regexp should show no default:\n%s", out)
+	}
+}
+
+func TestUsageZVStart(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	n, s, d := 3, "preset", 90*time.Second
+	fs.ZVIntVar(&n, "n", 5, "n")
+	fs.ZVStringVar(&s, "s", "example", "s")
+	fs.ZVDurationVar(&d, "d", 90*time.Second, "d")
+	fs.ZVPercent("p", 0.5, PercentReject, "p")
+	fs.ZVLogLevel("level", LevelWarn, "level")
+
+	if err := fs.Parse([]string{"-n=7", "-s=x"}); err != nil {
+		t.Fatal(err)
+	}
+	out := usage(fs)
+	for _, want := range []string{
+		// what the targets held when defined, not after parsing
+		"n (default 3)\n",
+		`s (default "preset")`,
+		"d (default 1m30s)\n",
+		"p (default 0)\n",
+		"level (default info)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("usage missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestUsageLabels(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("nd_int", 5, "nd int")
+	fs.ZVInt("zv_int", 6, "zv int")
+	fs.ZVIntDefault("zv_seeded", 7, "zv seeded")
+	fs.NDDuration("nd_duration", 0, "nd duration")
+	fs.ZVBool("zv_bool", true, "zv bool")
+	fs.NDStringSlice("nd_tag", "nd tags")
+	fs.ZVCount("zv_count", "zv count")
+
+	out := usage(fs)
+	for _, want := range []string{
+		"nd int (example 5)\n",
+		// plain zv flags start out at the zero value, not the example.
+		"zv int (default 0)\n",
+		"zv seeded (default 7)\n",
+		"nd duration (example 0s)\n",
+		"zv bool (default false)\n",
+		// nothing to show for flags without an example.
+		"nd tags\n",
+		"zv count\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("usage missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestUsageHeaderFooter(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("port", 0, "port to listen on")
+
+	if out := usage(fs); !strings.HasPrefix(out, "Usage of NDflag_test:\n") {
+		t.Errorf("default header missing:\n%s", out)
+	}
+
+	fs.SetUsageHeader("serve - a tiny server\n\nUsage: serve [flags] dir")
+	fs.SetUsageFooter("Example:\n  serve -port=8080 .\n")
+	out := usage(fs)
+	want := "serve - a tiny server\n\nUsage: serve [flags] dir\n" +
+		"  -port value\n    \tport to listen on (example 0)\n" +
+		"Example:\n  serve -port=8080 .\n"
+	if out != want {
+		t.Errorf("bad usage, got:\n%q\nwant:\n%q", out, want)
+	}
+}
+
+func TestUsageOrder(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("zone", "", "zone")
+	fs.ZVInt("port", 0, "port")
+	fs.NDBoolNegatable("cache", false, "cache")
+	fs.NDString("addr", "", "addr")
+	fs.Alias("port", "p")
+	fs.FlagSet.String("std", "", "defined on the stdlib set")
+
+	order := func(out string) string {
+		var names []string
+		for _, l := range strings.Split(out, "\n") {
+			if strings.HasPrefix(l, "  -") {
+				names = append(names, strings.Fields(l)[0])
+			}
+		}
+		return strings.Join(names, " ")
+	}
+
+	if got := order(usage(fs)); got != "-addr -cache -no-cache -port, -std -zone" {
+		t.Errorf("bad lexical order: %s", got)
+	}
+	fs.SetUsageOrder(OrderDeclared)
+	if got := order(usage(fs)); got != "-zone -port, -cache -no-cache -addr -std" {
+		t.Errorf("bad declared order: %s", got)
+	}
+	if got := order(usage(fs.Clone("clone"))); got != "-zone -port, -cache -no-cache -addr -std" {
+		t.Errorf("clone should keep the declared order: %s", got)
+	}
+	fs.SetUsageOrder(OrderLexical)
+	if got := order(usage(fs)); got != "-addr -cache -no-cache -port, -std -zone" {
+		t.Errorf("bad lexical order: %s", got)
+	}
+}
+
+func TestHide(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("name", "", "name")
+	secret := fs.NDString("secret", "", "internal knob")
+	fs.Alias("secret", "s")
+	fs.NDBoolNegatable("experiment", false, "try it")
+	fs.Hide("s")
+	fs.Hide("experiment")
+
+	out := usage(fs)
+	if !strings.Contains(out, "-name") {
+		t.Errorf("usage should list -name:\n%s", out)
+	}
+	for _, hidden := range []string{"-secret", "-s", "-experiment", "-no-experiment"} {
+		if strings.Contains(out, hidden) {
+			t.Errorf("usage shouldn't list %s:\n%s", hidden, out)
+		}
+	}
+	var buf bytes.Buffer
+	if err := fs.BashCompletion("prog", &buf); err != nil || strings.Contains(buf.String(), "-secret") {
+		t.Errorf("completion shouldn't list -secret: %v\n%s", err, buf.String())
+	}
+
+	if err := fs.Parse([]string{"-secret=x", "-no-experiment"}); err != nil {
+		t.Fatal(err)
+	}
+	if *secret == nil || **secret != "x" {
+		t.Errorf("hidden flags should still parse, got %v", *secret)
+	}
+
+	fs.SetShowHidden(true)
+	out = usage(fs)
+	for _, want := range []string{"-secret, -s", "-experiment", "-no-experiment"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("usage should list %s once shown:\n%s", want, out)
+		}
+	}
+}
+
+func TestUsageGroups(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("host", "", "host")
+	fs.ZVInt("port", 0, "port")
+	fs.NDString("log-level", "", "log level")
+	fs.NDBoolNegatable("color", true, "color output")
+	fs.NDString("name", "", "name")
+	fs.NDString("zone", "", "zone")
+	fs.Group("Networking", "port", "host")
+	fs.Group("Logging", "log-level", "color")
+	fs.Group("Networking", "zone", "host")
+
+	var lines []string
+	for _, l := range strings.Split(usage(fs), "\n") {
+		if strings.HasPrefix(l, "  -") {
+			l = strings.Fields(l)[0]
+		} else if strings.HasPrefix(l, " ") || l == "" {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	want := "Usage of NDflag_test:|Networking:|-port|-host|-zone|Logging:|-log-level|-color|-no-color|Other flags:|-name"
+	if got := strings.Join(lines, "|"); got != want {
+		t.Errorf("bad grouped usage:\n got %s\nwant %s", got, want)
+	}
+
+	fs = NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDString("host", "", "host")
+	fs.Group("Networking", "host")
+	if out := usage(fs); strings.Contains(out, "Other flags") {
+		t.Errorf("no Other heading without ungrouped flags:\n%s", out)
+	}
+}
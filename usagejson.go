@@ -0,0 +1,95 @@
+package nodefflag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// flagInfo - the description of a flag written by UsageJSON.  Fields are
+// only ever added, so the output stays readable by older tooling.
+type flagInfo struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Type    string   `json:"type"`
+	Kind    string   `json:"kind"`
+	Example string   `json:"example"`
+	Choices []string `json:"choices,omitempty"`
+	Usage   string   `json:"usage"`
+}
+
+// UsageJSON - writes a JSON array describing every flag, sorted by name,
+// for generating docs or completions.  Each object has:
+//
+//	name     the flag name, without the dash
+//	aliases  other names for the flag, if any
+//	type     the Go type of the value, e.g. int or []string
+//	kind     "nd", "zv", or "custom" for Values of your own
+//	example  the example the flag was defined with, which for the
+//	         ND*Default and ZV*Default methods is the default.  For
+//	         the plain ZV methods that's not what the usage shows,
+//	         the value the flag starts out with.
+//	choices  the accepted values of an enum flag
+//	usage    the usage text
+func (ndf *NDFlagSet) UsageJSON(w io.Writer) error {
+	infos := []flagInfo{}
+	ndf.visitCanonical(func(fl *flag.Flag) {
+		if ndf.isHidden(fl.Name) {
+			return
+		}
+		v := unwrapValue(fl.Value)
+		info := flagInfo{
+			Name:    fl.Name,
+			Aliases: ndf.aliasesOf(fl.Name),
+			Type:    valueType(v),
+			Kind:    valueKind(v),
+			Example: fl.DefValue,
+			Usage:   fl.Usage,
+		}
+		if c, ok := v.(choiceLister); ok {
+			info.Choices = c.choiceList()
+		}
+		infos = append(infos, info)
+	})
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(infos)
+}
+
+// valueKind - which family a Value belongs to, going by the markers on
+// the package's own types.
+func valueKind(v flag.Value) string {
+	switch v := v.(type) {
+	case *negbf:
+		return valueKind(unwrapValue(v.pos))
+	case funcf:
+		return "nd"
+	case zeroValuer:
+		return "zv"
+	case cloner:
+		return "nd"
+	}
+	return "custom"
+}
+
+// valueType - the Go type a Value holds, from what Get returns: ND values
+// return a pointer to it, ZV values the value itself.
+func valueType(v flag.Value) string {
+	switch v := v.(type) {
+	case *negbf:
+		return valueType(unwrapValue(v.pos))
+	case funcf:
+		return "func"
+	}
+	g, ok := v.(flag.Getter)
+	if !ok || g.Get() == nil {
+		return fmt.Sprintf("%T", v)
+	}
+	t := reflect.TypeOf(g.Get())
+	if valueKind(v) == "nd" && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.String()
+}
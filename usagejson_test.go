@@ -0,0 +1,49 @@
+package nodefflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type plainValue string
+
+func (p *plainValue) String() string     { return string(*p) }
+func (p *plainValue) Set(v string) error { *p = plainValue(v); return nil }
+
+func TestUsageJSON(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	fs.NDInt("port", 8080, "port to listen on")
+	fs.ZVDuration("timeout", time.Second, "request timeout")
+	fs.NDStringSlice("tag", "tags")
+	fs.ZVEnum("mode", []string{"fast", "safe"}, "mode")
+	fs.NDBoolNegatable("cache", true, "use the cache")
+	var pv plainValue
+	fs.Var(&pv, "custom", "custom value")
+	fs.Alias("port", "p")
+
+	var buf bytes.Buffer
+	if err := fs.UsageJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("bad JSON: %v\n%s", err, buf.String())
+	}
+
+	want := []map[string]interface{}{
+		{"name": "cache", "type": "bool", "kind": "nd", "example": "true", "usage": "use the cache"},
+		{"name": "custom", "type": "*nodefflag.plainValue", "kind": "custom", "example": "", "usage": "custom value"},
+		{"name": "mode", "type": "string", "kind": "zv", "example": "", "usage": "mode", "choices": []interface{}{"fast", "safe"}},
+		{"name": "no-cache", "type": "bool", "kind": "nd", "example": "false", "usage": "negates -cache"},
+		{"name": "port", "type": "int", "kind": "nd", "example": "8080", "usage": "port to listen on", "aliases": []interface{}{"p"}},
+		{"name": "tag", "type": "[]string", "kind": "nd", "example": "", "usage": "tags"},
+		{"name": "timeout", "type": "time.Duration", "kind": "zv", "example": "1s", "usage": "request timeout"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("bad usage JSON:\n%s", buf.String())
+	}
+}
@@ -1,184 +1,96 @@
 package nodefflag
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"image/color"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
-// implement the Value interface for flags
-type zvsf struct {
-	sv      *string
-	example string
+// zvenumf - string flag restricted to choices.
+type zvenumf struct {
+	*zvv[string]
+	choices []string
 }
 
-func (s *zvsf) String() string {
-	return s.example
+func (e *zvenumf) choiceList() []string {
+	return e.choices
 }
 
-func (s *zvsf) Set(val string) error {
-	*s.sv = val
-	return nil
-}
-
-func (s *zvsf) Get() interface{} {
-	return *s.sv
-}
-
-type zvbf struct {
-	bv      *bool
-	example string
-}
-
-func (b *zvbf) String() string {
-	return b.example
-}
-
-func (b *zvbf) Set(val string) error {
-	pb, err := strconv.ParseBool(val)
-	if err != nil {
-		return err
-	}
-	*b.bv = pb
-	return nil
-}
-
-func (b *zvbf) Get() interface{} {
-	return *b.bv
-}
-
-func (b *zvbf) IsBoolFlag() bool {
-	return true
+type zvcountf struct {
+	cv *int
 }
 
-type zvif struct {
-	iv      *int
-	example string
+func (c *zvcountf) String() string {
+	return ""
 }
 
-func (i *zvif) String() string {
-	return i.example
-}
-
-func (i *zvif) Set(val string) error {
-	pi, err := strconv.Atoi(val)
+func (c *zvcountf) Set(val string) error {
+	n, err := countVal(*c.cv, val)
 	if err != nil {
 		return err
 	}
-	*i.iv = pi
+	*c.cv = n
 	return nil
 }
 
-func (i *zvif) Get() interface{} {
-	return *i.iv
+func (c *zvcountf) Get() interface{} {
+	return *c.cv
 }
 
-type zvi64f struct {
-	iv      *int64
-	example string
-}
+func (c *zvcountf) repeatable() {}
 
-func (i *zvi64f) String() string {
-	return i.example
-}
+func (c *zvcountf) zeroValue() {}
 
-func (i *zvi64f) Set(val string) error {
-	pi, err := strconv.ParseInt(val, 10, 64)
-	if err != nil {
-		return err
-	}
-	*i.iv = pi
-	return nil
+func (c *zvcountf) reset() {
+	*c.cv = 0
 }
 
-func (i *zvi64f) Get() interface{} {
-	return *i.iv
+func (c *zvcountf) IsBoolFlag() bool {
+	return true
 }
 
-type zvuif struct {
-	uiv     *uint
-	example string
+type zvsmf struct {
+	mv   *map[string]string
+	opts MapOptions
 }
 
-func (ui *zvuif) String() string {
-	return ui.example
+func (m *zvsmf) String() string {
+	return ""
 }
 
-func (ui *zvuif) Set(val string) error {
-	pui, err := strconv.ParseUint(val, 10, 64)
+func (m *zvsmf) Set(val string) error {
+	kvs, err := m.opts.entries(val)
 	if err != nil {
 		return err
 	}
-	pui2 := uint(pui)
-	*ui.uiv = pui2
-	return nil
-}
-
-func (ui zvuif) Get() interface{} {
-	return *ui.uiv
-}
-
-type zvui64f struct {
-	uiv     *uint64
-	example string
-}
-
-func (ui *zvui64f) String() string {
-	return ui.example
-}
-
-func (ui *zvui64f) Set(val string) error {
-	pui, err := strconv.ParseUint(val, 10, 64)
-	if err != nil {
-		return err
+	if *m.mv == nil {
+		*m.mv = map[string]string{}
 	}
-	*ui.uiv = pui
-	return nil
-}
-
-func (ui *zvui64f) Get() interface{} { return *ui.uiv }
-
-type zvff struct {
-	fv      *float64
-	example string
-}
-
-func (f *zvff) String() string {
-	return f.example
-}
-
-func (f *zvff) Set(val string) error {
-	pf, err := strconv.ParseFloat(val, 64)
-	if err != nil {
-		return err
+	for _, kv := range kvs {
+		(*m.mv)[kv[0]] = kv[1]
 	}
-	*f.fv = pf
 	return nil
 }
 
-func (f *zvff) Get() interface{} {
-	return *f.fv
+func (m *zvsmf) Get() interface{} {
+	return *m.mv
 }
 
-type zvdff struct {
-	dv      *time.Duration
-	example string
-}
+func (m *zvsmf) repeatable() {}
 
-func (d *zvdff) String() string {
-	return d.example
-}
-
-func (d *zvdff) Set(val string) error {
-	pd, err := time.ParseDuration(val)
-	if err != nil {
-		return err
-	}
-	*d.dv = pd
-	return nil
-}
+func (m *zvsmf) zeroValue() {}
 
-func (d *zvdff) Get() interface{} {
-	return *d.dv
+func (m *zvsmf) reset() {
+	*m.mv = map[string]string{}
 }
 
 // ZVString - returns string pointer, will reference nil
@@ -192,10 +104,24 @@ func (ndf *NDFlagSet) ZVString(name, example, usage string) *string {
 // AVStringVar - Similar to AVString, but you supply the
 // string pointer.
 func (ndf *NDFlagSet) ZVStringVar(sv *string, name, example, usage string) {
-	s := &zvsf{sv: sv, example: example}
+	s := &zvv[string]{v: sv, parse: parseString, example: example, quoted: true, stdin: true}
 	ndf.Var(s, name, usage)
 }
 
+// ZVRune - single character flag, see NDRune.  returns pointer, which
+// references 0 if the flag was not set.
+func (ndf *NDFlagSet) ZVRune(name string, example rune, usage string) *rune {
+	var rv rune
+	ndf.ZVRuneVar(&rv, name, example, usage)
+	return &rv
+}
+
+// ZVRuneVar - BYO pointer version of ZVRune
+func (ndf *NDFlagSet) ZVRuneVar(rv *rune, name string, example rune, usage string) {
+	r := &zvv[rune]{v: rv, parse: parseRune, example: runeExample(example)}
+	ndf.Var(r, name, usage)
+}
+
 // ZVBool - returns bool pointer, will be
 // false if flag was not set, will be the parsed value otherwise.
 func (ndf *NDFlagSet) ZVBool(name string, example bool, usage string) *bool {
@@ -207,7 +133,21 @@ func (ndf *NDFlagSet) ZVBool(name string, example bool, usage string) *bool {
 // ZVBoolVar - similar to ZVBool, but you supply the
 // bool pointer.
 func (ndf *NDFlagSet) ZVBoolVar(bv *bool, name string, example bool, usage string) {
-	b := &zvbf{bv: bv, example: strconv.FormatBool(example)}
+	b := &zvv[bool]{v: bv, parse: strconv.ParseBool, example: strconv.FormatBool(example), isBool: true}
+	ndf.Var(b, name, usage)
+}
+
+// ZVBoolExtended - ZVBool that also accepts yes/no, y/n and on/off, see
+// NDBoolExtended.
+func (ndf *NDFlagSet) ZVBoolExtended(name string, example bool, usage string) *bool {
+	var bv bool
+	ndf.ZVBoolExtendedVar(&bv, name, example, usage)
+	return &bv
+}
+
+// ZVBoolExtendedVar - BYO pointer version of ZVBoolExtended
+func (ndf *NDFlagSet) ZVBoolExtendedVar(bv *bool, name string, example bool, usage string) {
+	b := &zvv[bool]{v: bv, parse: parseBoolExtended, example: strconv.FormatBool(example), isBool: true}
 	ndf.Var(b, name, usage)
 }
 
@@ -221,7 +161,7 @@ func (ndf *NDFlagSet) ZVInt(name string, example int, usage string) *int {
 
 // ZVIntVar - similar to ZVInt, but you supply the pointer.
 func (ndf *NDFlagSet) ZVIntVar(iv *int, name string, example int, usage string) {
-	i := &zvif{iv: iv, example: strconv.FormatInt(int64(example), 10)}
+	i := &zvv[int]{v: iv, parse: strconv.Atoi, example: strconv.FormatInt(int64(example), 10)}
 	ndf.Var(i, name, usage)
 }
 
@@ -234,7 +174,46 @@ func (ndf *NDFlagSet) ZVInt64(name string, example int64, usage string) *int64 {
 
 // ZVInt64Var - ZVIntVar but for int64
 func (ndf *NDFlagSet) ZVInt64Var(iv *int64, name string, example int64, usage string) {
-	i := &zvi64f{iv: iv, example: strconv.FormatInt(example, 10)}
+	i := &zvv[int64]{v: iv, parse: parseSigned[int64](64), example: strconv.FormatInt(example, 10)}
+	ndf.Var(i, name, usage)
+}
+
+// ZVInt8 - ZVInt but type is int8
+func (ndf *NDFlagSet) ZVInt8(name string, example int8, usage string) *int8 {
+	var iv int8
+	ndf.ZVInt8Var(&iv, name, example, usage)
+	return &iv
+}
+
+// ZVInt8Var - ZVIntVar but for int8
+func (ndf *NDFlagSet) ZVInt8Var(iv *int8, name string, example int8, usage string) {
+	i := &zvv[int8]{v: iv, parse: parseSigned[int8](8), example: strconv.FormatInt(int64(example), 10)}
+	ndf.Var(i, name, usage)
+}
+
+// ZVInt16 - ZVInt but type is int16
+func (ndf *NDFlagSet) ZVInt16(name string, example int16, usage string) *int16 {
+	var iv int16
+	ndf.ZVInt16Var(&iv, name, example, usage)
+	return &iv
+}
+
+// ZVInt16Var - ZVIntVar but for int16
+func (ndf *NDFlagSet) ZVInt16Var(iv *int16, name string, example int16, usage string) {
+	i := &zvv[int16]{v: iv, parse: parseSigned[int16](16), example: strconv.FormatInt(int64(example), 10)}
+	ndf.Var(i, name, usage)
+}
+
+// ZVInt32 - ZVInt but type is int32
+func (ndf *NDFlagSet) ZVInt32(name string, example int32, usage string) *int32 {
+	var iv int32
+	ndf.ZVInt32Var(&iv, name, example, usage)
+	return &iv
+}
+
+// ZVInt32Var - ZVIntVar but for int32
+func (ndf *NDFlagSet) ZVInt32Var(iv *int32, name string, example int32, usage string) {
+	i := &zvv[int32]{v: iv, parse: parseSigned[int32](32), example: strconv.FormatInt(int64(example), 10)}
 	ndf.Var(i, name, usage)
 }
 
@@ -247,7 +226,7 @@ func (ndf *NDFlagSet) ZVUint(name string, example uint, usage string) *uint {
 
 // ZVUintVar - same as ZVUint, but you supply the pointer.
 func (ndf *NDFlagSet) ZVUintVar(uiv *uint, name string, example uint, usage string) {
-	ui := &zvuif{uiv: uiv, example: strconv.FormatUint(uint64(example), 10)}
+	ui := &zvv[uint]{v: uiv, parse: parseUnsigned[uint](64), example: strconv.FormatUint(uint64(example), 10)}
 	ndf.Var(ui, name, usage)
 }
 
@@ -260,10 +239,63 @@ func (ndf *NDFlagSet) ZVUint64(name string, example uint64, usage string) *uint6
 
 // ZVUint64Var - uint64 version of ZVUintVar
 func (ndf *NDFlagSet) ZVUint64Var(uiv *uint64, name string, example uint64, usage string) {
-	ui := &zvui64f{uiv: uiv, example: strconv.FormatUint(example, 10)}
+	ui := &zvv[uint64]{v: uiv, parse: parseUnsigned[uint64](64), example: strconv.FormatUint(example, 10)}
+	ndf.Var(ui, name, usage)
+}
+
+// ZVUint8 - uint8 version of ZVUint
+func (ndf *NDFlagSet) ZVUint8(name string, example uint8, usage string) *uint8 {
+	var uiv uint8
+	ndf.ZVUint8Var(&uiv, name, example, usage)
+	return &uiv
+}
+
+// ZVUint8Var - uint8 version of ZVUintVar
+func (ndf *NDFlagSet) ZVUint8Var(uiv *uint8, name string, example uint8, usage string) {
+	ui := &zvv[uint8]{v: uiv, parse: parseUnsigned[uint8](8), example: strconv.FormatUint(uint64(example), 10)}
+	ndf.Var(ui, name, usage)
+}
+
+// ZVUint16 - uint16 version of ZVUint
+func (ndf *NDFlagSet) ZVUint16(name string, example uint16, usage string) *uint16 {
+	var uiv uint16
+	ndf.ZVUint16Var(&uiv, name, example, usage)
+	return &uiv
+}
+
+// ZVUint16Var - uint16 version of ZVUintVar
+func (ndf *NDFlagSet) ZVUint16Var(uiv *uint16, name string, example uint16, usage string) {
+	ui := &zvv[uint16]{v: uiv, parse: parseUnsigned[uint16](16), example: strconv.FormatUint(uint64(example), 10)}
+	ndf.Var(ui, name, usage)
+}
+
+// ZVUint32 - uint32 version of ZVUint
+func (ndf *NDFlagSet) ZVUint32(name string, example uint32, usage string) *uint32 {
+	var uiv uint32
+	ndf.ZVUint32Var(&uiv, name, example, usage)
+	return &uiv
+}
+
+// ZVUint32Var - uint32 version of ZVUintVar
+func (ndf *NDFlagSet) ZVUint32Var(uiv *uint32, name string, example uint32, usage string) {
+	ui := &zvv[uint32]{v: uiv, parse: parseUnsigned[uint32](32), example: strconv.FormatUint(uint64(example), 10)}
 	ndf.Var(ui, name, usage)
 }
 
+// ZVBigInt - arbitrary precision integer flag, see NDBigInt.  returns
+// pointer, which references 0 if the flag was not set.
+func (ndf *NDFlagSet) ZVBigInt(name string, example *big.Int, usage string) *big.Int {
+	var bv big.Int
+	ndf.ZVBigIntVar(&bv, name, example, usage)
+	return &bv
+}
+
+// ZVBigIntVar - BYO pointer version of ZVBigInt
+func (ndf *NDFlagSet) ZVBigIntVar(bv *big.Int, name string, example *big.Int, usage string) {
+	b := &zvv[big.Int]{v: bv, parse: parseBigInt, example: bigIntExample(example)}
+	ndf.Var(b, name, usage)
+}
+
 // ZVFloat64 - returns pointer to a float64.  Works the same
 // as all the other numeric types.
 func (ndf *NDFlagSet) ZVFloat64(name string, example float64, usage string) *float64 {
@@ -274,10 +306,135 @@ func (ndf *NDFlagSet) ZVFloat64(name string, example float64, usage string) *flo
 
 // ZVFloat64Var - you supply the pointer, but same as ZVFloat64
 func (ndf *NDFlagSet) ZVFloat64Var(fv *float64, name string, example float64, usage string) {
-	f := &zvff{fv: fv, example: strconv.FormatFloat(example, 'g', -1, 64)}
+	f := &zvv[float64]{v: fv, parse: parseFloat[float64](64), example: strconv.FormatFloat(example, 'g', -1, 64)}
+	ndf.Var(f, name, usage)
+}
+
+// ZVFloat32 - float32 version of ZVFloat64
+func (ndf *NDFlagSet) ZVFloat32(name string, example float32, usage string) *float32 {
+	var fv float32
+	ndf.ZVFloat32Var(&fv, name, example, usage)
+	return &fv
+}
+
+// ZVFloat32Var - float32 version of ZVFloat64Var
+func (ndf *NDFlagSet) ZVFloat32Var(fv *float32, name string, example float32, usage string) {
+	f := &zvv[float32]{v: fv, parse: parseFloat[float32](32), example: strconv.FormatFloat(float64(example), 'g', -1, 32)}
+	ndf.Var(f, name, usage)
+}
+
+// ZVUnit - number with a unit suffix, see NDUnit.  returns pointer,
+// which is 0 if the flag never appears.
+func (ndf *NDFlagSet) ZVUnit(name string, units map[string]float64, base string, example float64, usage string) *float64 {
+	var fv float64
+	ndf.ZVUnitVar(&fv, name, units, base, example, usage)
+	return &fv
+}
+
+// ZVUnitVar - BYO pointer version of ZVUnit
+func (ndf *NDFlagSet) ZVUnitVar(fv *float64, name string, units map[string]float64, base string, example float64, usage string) {
+	f := &zvv[float64]{v: fv, parse: parseUnit(units, base), example: unitExample(units, base, example)}
+	ndf.Var(f, name, usage)
+}
+
+// ZVPercent - fraction flag, see NDPercent.  returns pointer, which is 0
+// if the flag never appears.
+func (ndf *NDFlagSet) ZVPercent(name string, example float64, mode PercentMode, usage string) *float64 {
+	var fv float64
+	ndf.ZVPercentVar(&fv, name, example, mode, usage)
+	return &fv
+}
+
+// ZVPercentVar - BYO pointer version of ZVPercent
+func (ndf *NDFlagSet) ZVPercentVar(fv *float64, name string, example float64, mode PercentMode, usage string) {
+	f := &zvv[float64]{v: fv, parse: mode.parse, example: percentExample(example)}
 	ndf.Var(f, name, usage)
 }
 
+// ZVLogLevel - log level flag, see NDLogLevel.  returns pointer, which is
+// LevelInfo if the flag never appears.
+func (ndf *NDFlagSet) ZVLogLevel(name string, example LogLevel, usage string) *LogLevel {
+	var lv LogLevel
+	ndf.ZVLogLevelVar(&lv, name, example, usage)
+	return &lv
+}
+
+// ZVLogLevelVar - BYO pointer version of ZVLogLevel
+func (ndf *NDFlagSet) ZVLogLevelVar(lv *LogLevel, name string, example LogLevel, usage string) {
+	l := &zvv[LogLevel]{v: lv, parse: parseLogLevel, example: example.String()}
+	ndf.Var(l, name, usage)
+}
+
+// ZVColor - color flag, see NDColor.  returns pointer, which is
+// transparent black if the flag never appears.
+func (ndf *NDFlagSet) ZVColor(name string, example color.RGBA, usage string) *color.RGBA {
+	var cv color.RGBA
+	ndf.ZVColorVar(&cv, name, example, usage)
+	return &cv
+}
+
+// ZVColorVar - BYO pointer version of ZVColor
+func (ndf *NDFlagSet) ZVColorVar(cv *color.RGBA, name string, example color.RGBA, usage string) {
+	c := &zvv[color.RGBA]{v: cv, parse: parseRGBA, example: colorExample(example)}
+	ndf.Var(c, name, usage)
+}
+
+// ZVComplex128 - complex number flag, parsed with strconv.ParseComplex.
+// returns pointer
+func (ndf *NDFlagSet) ZVComplex128(name string, example complex128, usage string) *complex128 {
+	var cv complex128
+	ndf.ZVComplex128Var(&cv, name, example, usage)
+	return &cv
+}
+
+// ZVComplex128Var - BYO pointer version of ZVComplex128
+func (ndf *NDFlagSet) ZVComplex128Var(cv *complex128, name string, example complex128, usage string) {
+	c := &zvv[complex128]{v: cv, parse: parseComplex, example: strconv.FormatComplex(example, 'g', -1, 128)}
+	ndf.Var(c, name, usage)
+}
+
+// ZVBytes - byte size flag, see NDBytes.  returns pointer
+func (ndf *NDFlagSet) ZVBytes(name string, example int64, usage string) *int64 {
+	var bv int64
+	ndf.ZVBytesVar(&bv, name, example, usage)
+	return &bv
+}
+
+// ZVBytesVar - BYO pointer version of ZVBytes
+func (ndf *NDFlagSet) ZVBytesVar(bv *int64, name string, example int64, usage string) {
+	b := &zvv[int64]{v: bv, parse: parseBytes, example: strconv.FormatInt(example, 10)}
+	ndf.Var(b, name, usage)
+}
+
+// ZVBytesHex - binary flag given hex encoded, decoded with
+// hex.DecodeString.  returns pointer, which references a nil slice if
+// the flag was not set.
+func (ndf *NDFlagSet) ZVBytesHex(name string, example []byte, usage string) *[]byte {
+	var bv []byte
+	ndf.ZVBytesHexVar(&bv, name, example, usage)
+	return &bv
+}
+
+// ZVBytesHexVar - BYO pointer version of ZVBytesHex
+func (ndf *NDFlagSet) ZVBytesHexVar(bv *[]byte, name string, example []byte, usage string) {
+	b := &zvv[[]byte]{v: bv, parse: hex.DecodeString, example: hex.EncodeToString(example), stdin: true}
+	ndf.Var(b, name, usage)
+}
+
+// ZVBytesBase64 - same as ZVBytesHex, but given in standard, padded
+// base64.
+func (ndf *NDFlagSet) ZVBytesBase64(name string, example []byte, usage string) *[]byte {
+	var bv []byte
+	ndf.ZVBytesBase64Var(&bv, name, example, usage)
+	return &bv
+}
+
+// ZVBytesBase64Var - BYO pointer version of ZVBytesBase64
+func (ndf *NDFlagSet) ZVBytesBase64Var(bv *[]byte, name string, example []byte, usage string) {
+	b := &zvv[[]byte]{v: bv, parse: base64.StdEncoding.DecodeString, example: base64.StdEncoding.EncodeToString(example), stdin: true}
+	ndf.Var(b, name, usage)
+}
+
 // ZVDuration - duration flag.  returns pointer
 func (ndf *NDFlagSet) ZVDuration(name string, example time.Duration, usage string) *time.Duration {
 	var dv time.Duration
@@ -287,6 +444,380 @@ func (ndf *NDFlagSet) ZVDuration(name string, example time.Duration, usage strin
 
 // ZVDurationVar - BYO duration pp version of ZVDuration
 func (ndf *NDFlagSet) ZVDurationVar(dv *time.Duration, name string, example time.Duration, usage string) {
-	d := &zvdff{dv: dv, example: example.String()}
+	d := &zvv[time.Duration]{v: dv, parse: time.ParseDuration, example: example.String()}
+	ndf.Var(d, name, usage)
+}
+
+// ZVTime - time flag, parsed as time.RFC3339.  returns pointer
+func (ndf *NDFlagSet) ZVTime(name string, example time.Time, usage string) *time.Time {
+	return ndf.ZVTimeLayout(name, time.RFC3339, example, usage)
+}
+
+// ZVTimeVar - BYO time pointer version of ZVTime
+func (ndf *NDFlagSet) ZVTimeVar(tv *time.Time, name string, example time.Time, usage string) {
+	ndf.ZVTimeLayoutVar(tv, name, time.RFC3339, example, usage)
+}
+
+// ZVTimeLayout - same as ZVTime, but parses with the supplied layout
+// (see time.Parse).  The example is formatted with the same layout.
+func (ndf *NDFlagSet) ZVTimeLayout(name, layout string, example time.Time, usage string) *time.Time {
+	var tv time.Time
+	ndf.ZVTimeLayoutVar(&tv, name, layout, example, usage)
+	return &tv
+}
+
+// ZVTimeLayoutVar - BYO time pointer version of ZVTimeLayout
+func (ndf *NDFlagSet) ZVTimeLayoutVar(tv *time.Time, name, layout string, example time.Time, usage string) {
+	t := &zvv[time.Time]{v: tv, parse: parseTime(layout), example: example.Format(layout)}
+	ndf.Var(t, name, usage)
+}
+
+// ZVUnixTime - time flag given as seconds since the epoch, see
+// NDUnixTime.  returns pointer
+func (ndf *NDFlagSet) ZVUnixTime(name string, example time.Time, usage string) *time.Time {
+	var tv time.Time
+	ndf.ZVUnixTimeVar(&tv, name, example, usage)
+	return &tv
+}
+
+// ZVUnixTimeVar - BYO pointer version of ZVUnixTime
+func (ndf *NDFlagSet) ZVUnixTimeVar(tv *time.Time, name string, example time.Time, usage string) {
+	t := &zvv[time.Time]{v: tv, parse: parseUnixTime, example: unixExample(example)}
+	ndf.Var(t, name, usage)
+}
+
+// ZVIP - IP address flag, parsed with net.ParseIP.  returns pointer,
+// which references a nil IP if the flag was not set.
+func (ndf *NDFlagSet) ZVIP(name string, example net.IP, usage string) *net.IP {
+	var ipv net.IP
+	ndf.ZVIPVar(&ipv, name, example, usage)
+	return &ipv
+}
+
+// ZVIPVar - BYO IP pointer version of ZVIP
+func (ndf *NDFlagSet) ZVIPVar(ipv *net.IP, name string, example net.IP, usage string) {
+	ip := &zvv[net.IP]{v: ipv, parse: parseIP, example: example.String()}
+	ndf.Var(ip, name, usage)
+}
+
+// ZVIPNet - CIDR flag, parsed with net.ParseCIDR.  The host part of the
+// address is discarded.  returns pointer
+func (ndf *NDFlagSet) ZVIPNet(name string, example net.IPNet, usage string) *net.IPNet {
+	var nv net.IPNet
+	ndf.ZVIPNetVar(&nv, name, example, usage)
+	return &nv
+}
+
+// ZVIPNetVar - BYO IPNet pointer version of ZVIPNet
+func (ndf *NDFlagSet) ZVIPNetVar(nv *net.IPNet, name string, example net.IPNet, usage string) {
+	n := &zvv[net.IPNet]{v: nv, parse: parseIPNet(false), example: example.String()}
+	ndf.Var(n, name, usage)
+}
+
+// ZVIPNetHost - same as ZVIPNet, but the IP keeps the host part as given.
+func (ndf *NDFlagSet) ZVIPNetHost(name string, example net.IPNet, usage string) *net.IPNet {
+	var nv net.IPNet
+	ndf.ZVIPNetHostVar(&nv, name, example, usage)
+	return &nv
+}
+
+// ZVIPNetHostVar - BYO IPNet pointer version of ZVIPNetHost
+func (ndf *NDFlagSet) ZVIPNetHostVar(nv *net.IPNet, name string, example net.IPNet, usage string) {
+	n := &zvv[net.IPNet]{v: nv, parse: parseIPNet(true), example: example.String()}
+	ndf.Var(n, name, usage)
+}
+
+// ZVAddr - IP address flag backed by net/netip, parsed with
+// netip.ParseAddr.  returns pointer, which references the zero Addr
+// if the flag was not set.
+func (ndf *NDFlagSet) ZVAddr(name string, example netip.Addr, usage string) *netip.Addr {
+	var av netip.Addr
+	ndf.ZVAddrVar(&av, name, example, usage)
+	return &av
+}
+
+// ZVAddrVar - BYO Addr pointer version of ZVAddr
+func (ndf *NDFlagSet) ZVAddrVar(av *netip.Addr, name string, example netip.Addr, usage string) {
+	a := &zvv[netip.Addr]{v: av, parse: netip.ParseAddr, example: netipExample(example)}
+	ndf.Var(a, name, usage)
+}
+
+// ZVPrefix - CIDR flag backed by net/netip, parsed with
+// netip.ParsePrefix.  returns pointer, which references the zero Prefix
+// if the flag was not set.
+func (ndf *NDFlagSet) ZVPrefix(name string, example netip.Prefix, usage string) *netip.Prefix {
+	var pv netip.Prefix
+	ndf.ZVPrefixVar(&pv, name, example, usage)
+	return &pv
+}
+
+// ZVPrefixVar - BYO Prefix pointer version of ZVPrefix
+func (ndf *NDFlagSet) ZVPrefixVar(pv *netip.Prefix, name string, example netip.Prefix, usage string) {
+	p := &zvv[netip.Prefix]{v: pv, parse: netip.ParsePrefix, example: netipExample(example)}
+	ndf.Var(p, name, usage)
+}
+
+// ZVAddrPort - host:port flag backed by net/netip, see NDAddrPort.
+// returns pointer, which references the zero AddrPort if the flag was not
+// set.
+func (ndf *NDFlagSet) ZVAddrPort(name string, example netip.AddrPort, usage string) *netip.AddrPort {
+	var av netip.AddrPort
+	ndf.ZVAddrPortVar(&av, name, example, usage)
+	return &av
+}
+
+// ZVAddrPortVar - BYO AddrPort pointer version of ZVAddrPort
+func (ndf *NDFlagSet) ZVAddrPortVar(av *netip.AddrPort, name string, example netip.AddrPort, usage string) {
+	a := &zvv[netip.AddrPort]{v: av, parse: netip.ParseAddrPort, example: netipExample(example)}
+	ndf.Var(a, name, usage)
+}
+
+// ZVURL - url flag, parsed with url.Parse.  Relative urls are accepted.
+// returns pointer
+func (ndf *NDFlagSet) ZVURL(name string, example *url.URL, usage string) *url.URL {
+	var uv url.URL
+	ndf.ZVURLVar(&uv, name, example, usage)
+	return &uv
+}
+
+// ZVURLVar - BYO url pointer version of ZVURL
+func (ndf *NDFlagSet) ZVURLVar(uv *url.URL, name string, example *url.URL, usage string) {
+	u := &zvv[url.URL]{v: uv, parse: parseURL(false), example: urlExample(example)}
+	ndf.Var(u, name, usage)
+}
+
+// ZVAbsURL - same as ZVURL, but the url must have a scheme.
+func (ndf *NDFlagSet) ZVAbsURL(name string, example *url.URL, usage string) *url.URL {
+	var uv url.URL
+	ndf.ZVAbsURLVar(&uv, name, example, usage)
+	return &uv
+}
+
+// ZVAbsURLVar - BYO url pointer version of ZVAbsURL
+func (ndf *NDFlagSet) ZVAbsURLVar(uv *url.URL, name string, example *url.URL, usage string) {
+	u := &zvv[url.URL]{v: uv, parse: parseURL(true), example: urlExample(example)}
+	ndf.Var(u, name, usage)
+}
+
+// ZVRegexp - regular expression flag, compiled with regexp.Compile.  The
+// example is the pattern.  returns pointer, note the zero Regexp it
+// references until set isn't usable, check IsSet first.
+func (ndf *NDFlagSet) ZVRegexp(name, example, usage string) *regexp.Regexp {
+	var rv regexp.Regexp
+	ndf.ZVRegexpVar(&rv, name, example, usage)
+	return &rv
+}
+
+// ZVRegexpVar - BYO pointer version of ZVRegexp
+func (ndf *NDFlagSet) ZVRegexpVar(rv *regexp.Regexp, name, example, usage string) {
+	r := &zvv[regexp.Regexp]{v: rv, parse: parseRegexp, example: example, quoted: true}
+	ndf.Var(r, name, usage)
+}
+
+// ZVStringSlice - repeatable string flag.  Each occurrence appends to the
+// slice, which starts out empty but non-nil.
+func (ndf *NDFlagSet) ZVStringSlice(name, usage string) *[]string {
+	sv := []string{}
+	ndf.ZVStringSliceVar(&sv, name, usage)
+	return &sv
+}
+
+// ZVStringSliceVar - BYO pointer version of ZVStringSlice.  Occurrences
+// are appended to whatever the slice already holds.
+func (ndf *NDFlagSet) ZVStringSliceVar(sv *[]string, name, usage string) {
+	s := &zvsv[string]{v: sv, parse: parseString}
+	ndf.Var(s, name, usage)
+}
+
+// ZVStringSliceN - ZVStringSlice that must collect between min and max
+// values, see NDStringSliceN, absent flags included.
+func (ndf *NDFlagSet) ZVStringSliceN(name string, min, max int, usage string) *[]string {
+	sv := []string{}
+	ndf.ZVStringSliceNVar(&sv, name, min, max, usage)
+	return &sv
+}
+
+// ZVStringSliceNVar - BYO pointer version of ZVStringSliceN.  Values the
+// slice already holds count towards min and max.
+func (ndf *NDFlagSet) ZVStringSliceNVar(sv *[]string, name string, min, max int, usage string) {
+	ndf.ZVStringSliceVar(sv, name, usage)
+	ndf.addValidator(name, func(_ context.Context, v interface{}) error {
+		s, _ := v.([]string)
+		return checkCount(len(s), min, max)
+	})
+}
+
+// ZVIntSlice - repeatable int flag, see NDIntSlice.  The slice starts
+// out empty but non-nil.
+func (ndf *NDFlagSet) ZVIntSlice(name, usage string) *[]int {
+	iv := []int{}
+	ndf.ZVIntSliceVar(&iv, name, usage)
+	return &iv
+}
+
+// ZVIntSliceVar - BYO pointer version of ZVIntSlice
+func (ndf *NDFlagSet) ZVIntSliceVar(iv *[]int, name, usage string) {
+	i := &zvsv[int]{v: iv, parse: strconv.Atoi}
+	ndf.Var(i, name, usage)
+}
+
+// ZVInt32Slice - repeatable int32 flag, see NDInt32Slice.  The slice
+// starts out empty but non-nil.
+func (ndf *NDFlagSet) ZVInt32Slice(name, usage string) *[]int32 {
+	iv := []int32{}
+	ndf.ZVInt32SliceVar(&iv, name, usage)
+	return &iv
+}
+
+// ZVInt32SliceVar - BYO pointer version of ZVInt32Slice
+func (ndf *NDFlagSet) ZVInt32SliceVar(iv *[]int32, name, usage string) {
+	i := &zvsv[int32]{v: iv, parse: parseSigned[int32](32)}
+	ndf.Var(i, name, usage)
+}
+
+// ZVInt16Slice - int16 version of ZVInt32Slice
+func (ndf *NDFlagSet) ZVInt16Slice(name, usage string) *[]int16 {
+	iv := []int16{}
+	ndf.ZVInt16SliceVar(&iv, name, usage)
+	return &iv
+}
+
+// ZVInt16SliceVar - BYO pointer version of ZVInt16Slice
+func (ndf *NDFlagSet) ZVInt16SliceVar(iv *[]int16, name, usage string) {
+	i := &zvsv[int16]{v: iv, parse: parseSigned[int16](16)}
+	ndf.Var(i, name, usage)
+}
+
+// ZVFloat64Slice - repeatable float64 flag, see NDFloat64Slice.  The
+// slice starts out empty but non-nil.
+func (ndf *NDFlagSet) ZVFloat64Slice(name, usage string) *[]float64 {
+	fv := []float64{}
+	ndf.ZVFloat64SliceVar(&fv, name, usage)
+	return &fv
+}
+
+// ZVFloat64SliceVar - BYO pointer version of ZVFloat64Slice
+func (ndf *NDFlagSet) ZVFloat64SliceVar(fv *[]float64, name, usage string) {
+	f := &zvsv[float64]{v: fv, parse: parseFiniteFloat}
+	ndf.Var(f, name, usage)
+}
+
+// ZVDurationSlice - repeatable duration flag, see NDDurationSlice.  The
+// slice starts out empty but non-nil.
+func (ndf *NDFlagSet) ZVDurationSlice(name, usage string) *[]time.Duration {
+	dv := []time.Duration{}
+	ndf.ZVDurationSliceVar(&dv, name, usage)
+	return &dv
+}
+
+// ZVDurationSliceVar - BYO pointer version of ZVDurationSlice
+func (ndf *NDFlagSet) ZVDurationSliceVar(dv *[]time.Duration, name, usage string) {
+	d := &zvsv[time.Duration]{v: dv, parse: time.ParseDuration}
 	ndf.Var(d, name, usage)
 }
+
+// ZVStringMap - repeatable key=value flag, see NDStringMap.  The map
+// starts out empty but non-nil.
+func (ndf *NDFlagSet) ZVStringMap(name, usage string) *map[string]string {
+	mv := map[string]string{}
+	ndf.ZVStringMapVar(&mv, name, usage)
+	return &mv
+}
+
+// ZVStringMapVar - BYO pointer version of ZVStringMap
+func (ndf *NDFlagSet) ZVStringMapVar(mv *map[string]string, name, usage string) {
+	ndf.ZVStringMapOptsVar(mv, name, MapOptions{}, usage)
+}
+
+// ZVStringMapOpts - ZVStringMap with several items per occurrence, see
+// NDStringMapOpts
+func (ndf *NDFlagSet) ZVStringMapOpts(name string, opts MapOptions, usage string) *map[string]string {
+	mv := map[string]string{}
+	ndf.ZVStringMapOptsVar(&mv, name, opts, usage)
+	return &mv
+}
+
+// ZVStringMapOptsVar - BYO pointer version of ZVStringMapOpts
+func (ndf *NDFlagSet) ZVStringMapOptsVar(mv *map[string]string, name string, opts MapOptions, usage string) {
+	m := &zvsmf{mv: mv, opts: opts}
+	ndf.Var(m, name, usage)
+}
+
+// ZVCSVString - comma separated string flag, see NDCSVString.  returns
+// pointer
+func (ndf *NDFlagSet) ZVCSVString(name string, example []string, usage string) *[]string {
+	return ndf.ZVCSVStringOpts(name, example, CSVOptions{}, usage)
+}
+
+// ZVCSVStringVar - BYO pointer version of ZVCSVString
+func (ndf *NDFlagSet) ZVCSVStringVar(sv *[]string, name string, example []string, usage string) {
+	ndf.ZVCSVStringOptsVar(sv, name, example, CSVOptions{}, usage)
+}
+
+// ZVCSVStringOpts - ZVCSVString with a custom separator / empty handling.
+func (ndf *NDFlagSet) ZVCSVStringOpts(name string, example []string, opts CSVOptions, usage string) *[]string {
+	var sv []string
+	ndf.ZVCSVStringOptsVar(&sv, name, example, opts, usage)
+	return &sv
+}
+
+// ZVCSVStringOptsVar - BYO pointer version of ZVCSVStringOpts
+func (ndf *NDFlagSet) ZVCSVStringOptsVar(sv *[]string, name string, example []string, opts CSVOptions, usage string) {
+	s := &zvv[[]string]{v: sv, parse: opts.parse, example: strings.Join(example, opts.sep())}
+	ndf.Var(s, name, usage)
+}
+
+// ZVEnum - string flag that only accepts one of choices, matched case
+// sensitively.  returns pointer
+func (ndf *NDFlagSet) ZVEnum(name string, choices []string, usage string) *string {
+	return ndf.ZVEnumOpts(name, choices, EnumOptions{}, usage)
+}
+
+// ZVEnumVar - BYO pointer version of ZVEnum
+func (ndf *NDFlagSet) ZVEnumVar(sv *string, name string, choices []string, usage string) {
+	ndf.ZVEnumOptsVar(sv, name, choices, EnumOptions{}, usage)
+}
+
+// ZVEnumOpts - ZVEnum with matching options.
+func (ndf *NDFlagSet) ZVEnumOpts(name string, choices []string, opts EnumOptions, usage string) *string {
+	var sv string
+	ndf.ZVEnumOptsVar(&sv, name, choices, opts, usage)
+	return &sv
+}
+
+// ZVEnumOptsVar - BYO pointer version of ZVEnumOpts
+func (ndf *NDFlagSet) ZVEnumOptsVar(sv *string, name string, choices []string, opts EnumOptions, usage string) {
+	e := &zvenumf{
+		zvv:     &zvv[string]{v: sv, parse: opts.parser(choices)},
+		choices: choices,
+	}
+	ndf.Var(e, name, usage)
+}
+
+// ZVPath - path flag, see NDPath.  returns pointer, which is "" if the
+// flag never appears.
+func (ndf *NDFlagSet) ZVPath(name, example string, opts PathOptions, usage string) *string {
+	var sv string
+	ndf.ZVPathVar(&sv, name, example, opts, usage)
+	return &sv
+}
+
+// ZVPathVar - BYO pointer version of ZVPath
+func (ndf *NDFlagSet) ZVPathVar(sv *string, name, example string, opts PathOptions, usage string) {
+	s := &zvv[string]{v: sv, parse: opts.parse, example: example, quoted: true}
+	ndf.Var(s, name, usage)
+}
+
+// ZVCount - counting flag, see NDCount.  returns pointer, which is 0 if
+// the flag never appears.
+func (ndf *NDFlagSet) ZVCount(name, usage string) *int {
+	var cv int
+	ndf.ZVCountVar(&cv, name, usage)
+	return &cv
+}
+
+// ZVCountVar - BYO pointer version of ZVCount
+func (ndf *NDFlagSet) ZVCountVar(cv *int, name, usage string) {
+	c := &zvcountf{cv: cv}
+	ndf.Var(c, name, usage)
+}
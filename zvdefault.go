@@ -0,0 +1,375 @@
+package nodefflag
+
+import (
+	"net"
+	"net/netip"
+	"time"
+)
+
+// The ZV*Default methods are the ZV methods with a real default: the
+// target starts out holding the value given, so that's the default the
+// usage shows rather than the zero value, and Reset puts it back there.
+// They are separate methods as the plain ZV ones have always started at
+// the zero value, and code relies on that.
+
+// seedDefault - the second half of every ZV*Default method, seeds the
+// target of the just registered flag with def and makes def what Reset
+// goes back to.
+func seedDefault[T any](ndf *NDFlagSet, name string, v *T, def T) {
+	*v = def
+	if z, ok := ndf.tracked[name].Value.(*zvv[T]); ok {
+		z.def = &def
+	}
+}
+
+// ZVValueDefault - ZVValue seeded with def, see ZVIntDefault.
+func ZVValueDefault[T any](ndf *NDFlagSet, name string, parse func(string) (T, error), def T, usage string) *T {
+	var v T
+	ZVValueDefaultVar(ndf, &v, name, parse, def, usage)
+	return &v
+}
+
+// ZVValueDefaultVar - BYO pointer version of ZVValueDefault
+func ZVValueDefaultVar[T any](ndf *NDFlagSet, v *T, name string, parse func(string) (T, error), def T, usage string) {
+	ZVValueVar(ndf, v, name, parse, def, usage)
+	seedDefault(ndf, name, v, def)
+}
+
+// ZVStringDefault - ZVString seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVStringDefault(name string, def string, usage string) *string {
+	var sv string
+	ndf.ZVStringDefaultVar(&sv, name, def, usage)
+	return &sv
+}
+
+// ZVStringDefaultVar - BYO pointer version of ZVStringDefault
+func (ndf *NDFlagSet) ZVStringDefaultVar(sv *string, name string, def string, usage string) {
+	ndf.ZVStringVar(sv, name, def, usage)
+	seedDefault(ndf, name, sv, def)
+}
+
+// ZVBoolDefault - ZVBool seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVBoolDefault(name string, def bool, usage string) *bool {
+	var bv bool
+	ndf.ZVBoolDefaultVar(&bv, name, def, usage)
+	return &bv
+}
+
+// ZVBoolDefaultVar - BYO pointer version of ZVBoolDefault
+func (ndf *NDFlagSet) ZVBoolDefaultVar(bv *bool, name string, def bool, usage string) {
+	ndf.ZVBoolVar(bv, name, def, usage)
+	seedDefault(ndf, name, bv, def)
+}
+
+// ZVIntDefault - same as ZVInt, but the returned pointer references def
+// until the flag is set, rather than 0.
+func (ndf *NDFlagSet) ZVIntDefault(name string, def int, usage string) *int {
+	var iv int
+	ndf.ZVIntDefaultVar(&iv, name, def, usage)
+	return &iv
+}
+
+// ZVIntDefaultVar - BYO pointer version of ZVIntDefault
+func (ndf *NDFlagSet) ZVIntDefaultVar(iv *int, name string, def int, usage string) {
+	ndf.ZVIntVar(iv, name, def, usage)
+	seedDefault(ndf, name, iv, def)
+}
+
+// ZVInt64Default - ZVInt64 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVInt64Default(name string, def int64, usage string) *int64 {
+	var iv int64
+	ndf.ZVInt64DefaultVar(&iv, name, def, usage)
+	return &iv
+}
+
+// ZVInt64DefaultVar - BYO pointer version of ZVInt64Default
+func (ndf *NDFlagSet) ZVInt64DefaultVar(iv *int64, name string, def int64, usage string) {
+	ndf.ZVInt64Var(iv, name, def, usage)
+	seedDefault(ndf, name, iv, def)
+}
+
+// ZVInt8Default - ZVInt8 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVInt8Default(name string, def int8, usage string) *int8 {
+	var iv int8
+	ndf.ZVInt8DefaultVar(&iv, name, def, usage)
+	return &iv
+}
+
+// ZVInt8DefaultVar - BYO pointer version of ZVInt8Default
+func (ndf *NDFlagSet) ZVInt8DefaultVar(iv *int8, name string, def int8, usage string) {
+	ndf.ZVInt8Var(iv, name, def, usage)
+	seedDefault(ndf, name, iv, def)
+}
+
+// ZVInt16Default - ZVInt16 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVInt16Default(name string, def int16, usage string) *int16 {
+	var iv int16
+	ndf.ZVInt16DefaultVar(&iv, name, def, usage)
+	return &iv
+}
+
+// ZVInt16DefaultVar - BYO pointer version of ZVInt16Default
+func (ndf *NDFlagSet) ZVInt16DefaultVar(iv *int16, name string, def int16, usage string) {
+	ndf.ZVInt16Var(iv, name, def, usage)
+	seedDefault(ndf, name, iv, def)
+}
+
+// ZVInt32Default - ZVInt32 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVInt32Default(name string, def int32, usage string) *int32 {
+	var iv int32
+	ndf.ZVInt32DefaultVar(&iv, name, def, usage)
+	return &iv
+}
+
+// ZVInt32DefaultVar - BYO pointer version of ZVInt32Default
+func (ndf *NDFlagSet) ZVInt32DefaultVar(iv *int32, name string, def int32, usage string) {
+	ndf.ZVInt32Var(iv, name, def, usage)
+	seedDefault(ndf, name, iv, def)
+}
+
+// ZVUintDefault - ZVUint seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVUintDefault(name string, def uint, usage string) *uint {
+	var uiv uint
+	ndf.ZVUintDefaultVar(&uiv, name, def, usage)
+	return &uiv
+}
+
+// ZVUintDefaultVar - BYO pointer version of ZVUintDefault
+func (ndf *NDFlagSet) ZVUintDefaultVar(uiv *uint, name string, def uint, usage string) {
+	ndf.ZVUintVar(uiv, name, def, usage)
+	seedDefault(ndf, name, uiv, def)
+}
+
+// ZVUint64Default - ZVUint64 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVUint64Default(name string, def uint64, usage string) *uint64 {
+	var uiv uint64
+	ndf.ZVUint64DefaultVar(&uiv, name, def, usage)
+	return &uiv
+}
+
+// ZVUint64DefaultVar - BYO pointer version of ZVUint64Default
+func (ndf *NDFlagSet) ZVUint64DefaultVar(uiv *uint64, name string, def uint64, usage string) {
+	ndf.ZVUint64Var(uiv, name, def, usage)
+	seedDefault(ndf, name, uiv, def)
+}
+
+// ZVUint8Default - ZVUint8 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVUint8Default(name string, def uint8, usage string) *uint8 {
+	var uiv uint8
+	ndf.ZVUint8DefaultVar(&uiv, name, def, usage)
+	return &uiv
+}
+
+// ZVUint8DefaultVar - BYO pointer version of ZVUint8Default
+func (ndf *NDFlagSet) ZVUint8DefaultVar(uiv *uint8, name string, def uint8, usage string) {
+	ndf.ZVUint8Var(uiv, name, def, usage)
+	seedDefault(ndf, name, uiv, def)
+}
+
+// ZVUint16Default - ZVUint16 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVUint16Default(name string, def uint16, usage string) *uint16 {
+	var uiv uint16
+	ndf.ZVUint16DefaultVar(&uiv, name, def, usage)
+	return &uiv
+}
+
+// ZVUint16DefaultVar - BYO pointer version of ZVUint16Default
+func (ndf *NDFlagSet) ZVUint16DefaultVar(uiv *uint16, name string, def uint16, usage string) {
+	ndf.ZVUint16Var(uiv, name, def, usage)
+	seedDefault(ndf, name, uiv, def)
+}
+
+// ZVUint32Default - ZVUint32 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVUint32Default(name string, def uint32, usage string) *uint32 {
+	var uiv uint32
+	ndf.ZVUint32DefaultVar(&uiv, name, def, usage)
+	return &uiv
+}
+
+// ZVUint32DefaultVar - BYO pointer version of ZVUint32Default
+func (ndf *NDFlagSet) ZVUint32DefaultVar(uiv *uint32, name string, def uint32, usage string) {
+	ndf.ZVUint32Var(uiv, name, def, usage)
+	seedDefault(ndf, name, uiv, def)
+}
+
+// ZVFloat64Default - ZVFloat64 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVFloat64Default(name string, def float64, usage string) *float64 {
+	var fv float64
+	ndf.ZVFloat64DefaultVar(&fv, name, def, usage)
+	return &fv
+}
+
+// ZVFloat64DefaultVar - BYO pointer version of ZVFloat64Default
+func (ndf *NDFlagSet) ZVFloat64DefaultVar(fv *float64, name string, def float64, usage string) {
+	ndf.ZVFloat64Var(fv, name, def, usage)
+	seedDefault(ndf, name, fv, def)
+}
+
+// ZVFloat32Default - ZVFloat32 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVFloat32Default(name string, def float32, usage string) *float32 {
+	var fv float32
+	ndf.ZVFloat32DefaultVar(&fv, name, def, usage)
+	return &fv
+}
+
+// ZVFloat32DefaultVar - BYO pointer version of ZVFloat32Default
+func (ndf *NDFlagSet) ZVFloat32DefaultVar(fv *float32, name string, def float32, usage string) {
+	ndf.ZVFloat32Var(fv, name, def, usage)
+	seedDefault(ndf, name, fv, def)
+}
+
+// ZVBytesDefault - ZVBytes seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVBytesDefault(name string, def int64, usage string) *int64 {
+	var bv int64
+	ndf.ZVBytesDefaultVar(&bv, name, def, usage)
+	return &bv
+}
+
+// ZVBytesDefaultVar - BYO pointer version of ZVBytesDefault
+func (ndf *NDFlagSet) ZVBytesDefaultVar(bv *int64, name string, def int64, usage string) {
+	ndf.ZVBytesVar(bv, name, def, usage)
+	seedDefault(ndf, name, bv, def)
+}
+
+// ZVDurationDefault - ZVDuration seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVDurationDefault(name string, def time.Duration, usage string) *time.Duration {
+	var dv time.Duration
+	ndf.ZVDurationDefaultVar(&dv, name, def, usage)
+	return &dv
+}
+
+// ZVDurationDefaultVar - BYO pointer version of ZVDurationDefault
+func (ndf *NDFlagSet) ZVDurationDefaultVar(dv *time.Duration, name string, def time.Duration, usage string) {
+	ndf.ZVDurationVar(dv, name, def, usage)
+	seedDefault(ndf, name, dv, def)
+}
+
+// ZVTimeDefault - ZVTime seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVTimeDefault(name string, def time.Time, usage string) *time.Time {
+	var tv time.Time
+	ndf.ZVTimeDefaultVar(&tv, name, def, usage)
+	return &tv
+}
+
+// ZVTimeDefaultVar - BYO pointer version of ZVTimeDefault
+func (ndf *NDFlagSet) ZVTimeDefaultVar(tv *time.Time, name string, def time.Time, usage string) {
+	ndf.ZVTimeVar(tv, name, def, usage)
+	seedDefault(ndf, name, tv, def)
+}
+
+// ZVIPDefault - ZVIP seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVIPDefault(name string, def net.IP, usage string) *net.IP {
+	var ipv net.IP
+	ndf.ZVIPDefaultVar(&ipv, name, def, usage)
+	return &ipv
+}
+
+// ZVIPDefaultVar - BYO pointer version of ZVIPDefault
+func (ndf *NDFlagSet) ZVIPDefaultVar(ipv *net.IP, name string, def net.IP, usage string) {
+	ndf.ZVIPVar(ipv, name, def, usage)
+	seedDefault(ndf, name, ipv, def)
+}
+
+// ZVIPNetDefault - ZVIPNet seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVIPNetDefault(name string, def net.IPNet, usage string) *net.IPNet {
+	var nv net.IPNet
+	ndf.ZVIPNetDefaultVar(&nv, name, def, usage)
+	return &nv
+}
+
+// ZVIPNetDefaultVar - BYO pointer version of ZVIPNetDefault
+func (ndf *NDFlagSet) ZVIPNetDefaultVar(nv *net.IPNet, name string, def net.IPNet, usage string) {
+	ndf.ZVIPNetVar(nv, name, def, usage)
+	seedDefault(ndf, name, nv, def)
+}
+
+// ZVIPNetHostDefault - ZVIPNetHost seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVIPNetHostDefault(name string, def net.IPNet, usage string) *net.IPNet {
+	var nv net.IPNet
+	ndf.ZVIPNetHostDefaultVar(&nv, name, def, usage)
+	return &nv
+}
+
+// ZVIPNetHostDefaultVar - BYO pointer version of ZVIPNetHostDefault
+func (ndf *NDFlagSet) ZVIPNetHostDefaultVar(nv *net.IPNet, name string, def net.IPNet, usage string) {
+	ndf.ZVIPNetHostVar(nv, name, def, usage)
+	seedDefault(ndf, name, nv, def)
+}
+
+// ZVAddrDefault - ZVAddr seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVAddrDefault(name string, def netip.Addr, usage string) *netip.Addr {
+	var av netip.Addr
+	ndf.ZVAddrDefaultVar(&av, name, def, usage)
+	return &av
+}
+
+// ZVAddrDefaultVar - BYO pointer version of ZVAddrDefault
+func (ndf *NDFlagSet) ZVAddrDefaultVar(av *netip.Addr, name string, def netip.Addr, usage string) {
+	ndf.ZVAddrVar(av, name, def, usage)
+	seedDefault(ndf, name, av, def)
+}
+
+// ZVPrefixDefault - ZVPrefix seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVPrefixDefault(name string, def netip.Prefix, usage string) *netip.Prefix {
+	var pv netip.Prefix
+	ndf.ZVPrefixDefaultVar(&pv, name, def, usage)
+	return &pv
+}
+
+// ZVPrefixDefaultVar - BYO pointer version of ZVPrefixDefault
+func (ndf *NDFlagSet) ZVPrefixDefaultVar(pv *netip.Prefix, name string, def netip.Prefix, usage string) {
+	ndf.ZVPrefixVar(pv, name, def, usage)
+	seedDefault(ndf, name, pv, def)
+}
+
+// ZVAddrPortDefault - ZVAddrPort seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVAddrPortDefault(name string, def netip.AddrPort, usage string) *netip.AddrPort {
+	var av netip.AddrPort
+	ndf.ZVAddrPortDefaultVar(&av, name, def, usage)
+	return &av
+}
+
+// ZVAddrPortDefaultVar - BYO pointer version of ZVAddrPortDefault
+func (ndf *NDFlagSet) ZVAddrPortDefaultVar(av *netip.AddrPort, name string, def netip.AddrPort, usage string) {
+	ndf.ZVAddrPortVar(av, name, def, usage)
+	seedDefault(ndf, name, av, def)
+}
+
+// ZVBytesHexDefault - ZVBytesHex seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVBytesHexDefault(name string, def []byte, usage string) *[]byte {
+	var bv []byte
+	ndf.ZVBytesHexDefaultVar(&bv, name, def, usage)
+	return &bv
+}
+
+// ZVBytesHexDefaultVar - BYO pointer version of ZVBytesHexDefault
+func (ndf *NDFlagSet) ZVBytesHexDefaultVar(bv *[]byte, name string, def []byte, usage string) {
+	ndf.ZVBytesHexVar(bv, name, def, usage)
+	seedDefault(ndf, name, bv, def)
+}
+
+// ZVBytesBase64Default - ZVBytesBase64 seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVBytesBase64Default(name string, def []byte, usage string) *[]byte {
+	var bv []byte
+	ndf.ZVBytesBase64DefaultVar(&bv, name, def, usage)
+	return &bv
+}
+
+// ZVBytesBase64DefaultVar - BYO pointer version of ZVBytesBase64Default
+func (ndf *NDFlagSet) ZVBytesBase64DefaultVar(bv *[]byte, name string, def []byte, usage string) {
+	ndf.ZVBytesBase64Var(bv, name, def, usage)
+	seedDefault(ndf, name, bv, def)
+}
+
+// ZVCSVStringDefault - ZVCSVString seeded with def, see ZVIntDefault.
+func (ndf *NDFlagSet) ZVCSVStringDefault(name string, def []string, usage string) *[]string {
+	var sv []string
+	ndf.ZVCSVStringDefaultVar(&sv, name, def, usage)
+	return &sv
+}
+
+// ZVCSVStringDefaultVar - BYO pointer version of ZVCSVStringDefault
+func (ndf *NDFlagSet) ZVCSVStringDefaultVar(sv *[]string, name string, def []string, usage string) {
+	ndf.ZVCSVStringVar(sv, name, def, usage)
+	seedDefault(ndf, name, sv, def)
+}
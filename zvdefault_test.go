@@ -0,0 +1,96 @@
+package nodefflag
+
+import (
+	"flag"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestZVDefault(t *testing.T) {
+	fs := NewNDFlagSet("ZVflag_test", flag.ContinueOnError)
+	n := fs.ZVIntDefault("n", 5, "int value")
+	s := fs.ZVStringDefault("s", "hello", "string value")
+	b := fs.ZVBoolDefault("b", true, "bool value")
+	d := fs.ZVDurationDefault("d", time.Minute, "duration value")
+	a := fs.ZVAddrDefault("a", netip.MustParseAddr("127.0.0.1"), "addr value")
+	c := fs.ZVCSVStringDefault("c", []string{"x", "y"}, "csv value")
+	g := ZVValueDefault(fs, "g", parseColor, green, "color value")
+	plain := fs.ZVInt("plain", 7, "int value")
+
+	if *n != 5 || *s != "hello" || !*b || *d != time.Minute || a.String() != "127.0.0.1" ||
+		strings.Join(*c, ",") != "x,y" || *g != green {
+		t.Errorf("defaults not seeded: %d %q %v %v %v %q %v", *n, *s, *b, *d, *a, *c, *g)
+	}
+	if *plain != 0 {
+		t.Errorf("plain ZV should still start at 0, got %d", *plain)
+	}
+	if fs.IsSet("n") {
+		t.Error("seeded flag should not count as set")
+	}
+	if v, ok := fs.IntValue("n"); ok || v != 0 {
+		t.Errorf("IntValue only reports set flags, got %d, %v", v, ok)
+	}
+
+	if err := fs.Parse([]string{"-n=0", "-s", "", "-b=false"}); err != nil {
+		t.Fatal(err)
+	}
+	if *n != 0 || *s != "" || *b || *d != time.Minute {
+		t.Errorf("bad values after parse: %d %q %v %v", *n, *s, *b, *d)
+	}
+
+	fs.Reset()
+	if *n != 5 || *s != "hello" || !*b {
+		t.Errorf("Reset should go back to the defaults: %d %q %v", *n, *s, *b)
+	}
+
+	cl := fs.Clone("clone")
+	if v := cl.Lookup("n").Value.(flag.Getter).Get(); v != 5 {
+		t.Errorf("clone should be seeded too, got %v", v)
+	}
+}
+
+func TestZVDefaultUsage(t *testing.T) {
+	fs := NewNDFlagSet("ZVflag_test", flag.ContinueOnError)
+	fs.ZVIntDefault("workers", 4, "number of workers")
+	fs.ZVInt("threads", 4, "number of threads")
+	u := usage(fs)
+	if !strings.Contains(u, "workers (default 4)") {
+		t.Errorf("default not in usage:\n%s", u)
+	}
+	if !strings.Contains(u, "threads (default 0)") {
+		t.Errorf("a plain ZV flag should show the zero value it starts at:\n%s", u)
+	}
+}
+
+func TestWasDefaulted(t *testing.T) {
+	fs := NewNDFlagSet("NDflag_test", flag.ContinueOnError)
+	host := fs.ZVStringDefault("host", "localhost", "host")
+	fs.ZVIntDefault("port", 8080, "port")
+	fs.ZVBool("debug", false, "debug")
+	fs.NDString("name", "", "name")
+
+	if err := fs.Parse([]string{"-port=8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if !fs.WasDefaulted("host") || *host != "localhost" {
+		t.Errorf("host should have kept its default, got %q", *host)
+	}
+	if fs.WasDefaulted("port") {
+		t.Errorf("port was set explicitly, even if to its default")
+	}
+	if !fs.WasDefaulted("debug") {
+		t.Errorf("debug should have kept its zero value")
+	}
+	for _, name := range []string{"name", "nope"} {
+		if fs.WasDefaulted(name) {
+			t.Errorf("%s has no default", name)
+		}
+	}
+	for _, name := range []string{"host", "port", "debug"} {
+		if fs.WasDefaulted(name) == fs.IsSet(name) {
+			t.Errorf("%s: WasDefaulted should be the inverse of IsSet", name)
+		}
+	}
+}